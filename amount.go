@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+// amountDecimals is --decimals, -1 when not given.
+var amountDecimals int
+
+// amountUnits are the unit suffixes accepted besides token symbols.
+var amountUnits = map[string]uint8{
+	"wei":   0,
+	"gwei":  9,
+	"ether": 18,
+}
+
+var amountPattern = regexp.MustCompile(`^([0-9]+(?:\.[0-9]*)?|\.[0-9]+)\s*([A-Za-z][A-Za-z0-9]*)?$`)
+
+func registerAmountFlags() {
+	rootCmd.PersistentFlags().IntVar(&amountDecimals, "decimals", -1, "Decimals of --amount, which then takes token units such as 1000.5")
+}
+
+// parseAmount turns an amount flag into base units. Plain integers are base
+// units as before; a fraction, --decimals or a unit suffix (wei, gwei, ether or
+// a token symbol from the registry, e.g. 32ether or 1000.5USDT) switches to
+// token units. asset, when known, supplies the decimals of a bare fraction.
+// Amounts that do not fit the decimals are rejected instead of truncated.
+func parseAmount(value string, asset *registryAsset) (*big.Int, error) {
+	amount, err := scaleAmount(value, asset)
+	if err != nil {
+		return nil, withCode(errCodeInvalidArguments, err)
+	}
+	return amount, nil
+}
+
+func scaleAmount(value string, asset *registryAsset) (*big.Int, error) {
+	if err := validateAmountSign(value); err != nil {
+		return nil, err
+	}
+	match := amountPattern.FindStringSubmatch(strings.TrimSpace(value))
+	if match == nil {
+		return nil, fmt.Errorf("invalid amount: %q", value)
+	}
+	number, unit := match[1], match[2]
+
+	var decimals uint8
+	symbol := ""
+	scaled := true
+	switch {
+	case unit != "":
+		if d, ok := amountUnits[strings.ToLower(unit)]; ok {
+			decimals, symbol = d, strings.ToLower(unit)
+			break
+		}
+		chainID := layerZeroID
+		if asset != nil {
+			chainID = asset.ChainID
+		}
+		reg, err := loadRegistry()
+		if err != nil {
+			return nil, err
+		}
+		token := reg.findSymbol(unit, chainID)
+		if token == nil {
+			return nil, fmt.Errorf("unknown unit %q in amount %q, expected wei, gwei, ether or a token symbol from the registry", unit, value)
+		}
+		if asset != nil && asset.Address != "" && token.Address != "" && !strings.EqualFold(token.Address, asset.Address) {
+			return nil, fmt.Errorf("amount is in %s but the asset is %s", token.Symbol, asset.Address)
+		}
+		decimals, symbol = token.Decimals, token.Symbol
+	case amountDecimals >= 0:
+		if amountDecimals > 77 {
+			return nil, fmt.Errorf("--decimals %d is out of range", amountDecimals)
+		}
+		decimals = uint8(amountDecimals)
+		if asset != nil {
+			symbol = asset.Symbol
+			if asset.Decimals != decimals {
+				fmt.Fprintf(textOut, "Note: --decimals %d differs from the registry's %d decimals for %s\n", decimals, asset.Decimals, asset.Symbol)
+			}
+		}
+	case strings.Contains(number, "."):
+		if asset == nil {
+			return nil, fmt.Errorf("amount %q has a fraction, set --decimals or add the token to the registry", value)
+		}
+		decimals, symbol = asset.Decimals, asset.Symbol
+	default:
+		scaled = false
+	}
+
+	if !scaled {
+		raw, ok := new(big.Int).SetString(number, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid amount: %q", value)
+		}
+		if raw.Sign() == 0 {
+			return nil, fmt.Errorf("amount must be greater than zero")
+		}
+		if asset != nil {
+			fmt.Fprintf(textOut, "Amount: %s base units = %s %s (%d decimals)\n", raw, formatUnits(raw, asset.Decimals), asset.Symbol, asset.Decimals)
+		}
+		return raw, nil
+	}
+
+	raw, err := scaleUnits(number, decimals)
+	if err != nil {
+		return nil, fmt.Errorf("amount %q: %v", value, err)
+	}
+	if raw.Sign() == 0 {
+		return nil, fmt.Errorf("amount must be greater than zero")
+	}
+	fmt.Fprintf(textOut, "Amount: %s %s = %s base units (%d decimals)\n", formatUnits(raw, decimals), symbol, raw, decimals)
+	return raw, nil
+}
+
+// scaleUnits multiplies a decimal string by 10^decimals, failing rather than
+// dropping digits beyond the token's precision.
+func scaleUnits(number string, decimals uint8) (*big.Int, error) {
+	whole, frac, _ := strings.Cut(number, ".")
+	frac = strings.TrimRight(frac, "0")
+	if len(frac) > int(decimals) {
+		return nil, fmt.Errorf("more than %d decimal places would lose precision", decimals)
+	}
+	digits := whole + frac + strings.Repeat("0", int(decimals)-len(frac))
+	raw, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid number %q", number)
+	}
+	return raw, nil
+}
+
+// formatUnits renders a raw integer amount in token units, e.g. 1500000000000000000
+// with 18 decimals as 1.5.
+func formatUnits(amount *big.Int, decimals uint8) string {
+	if decimals == 0 {
+		return amount.String()
+	}
+	negative := amount.Sign() < 0
+	digits := new(big.Int).Abs(amount).String()
+	if len(digits) <= int(decimals) {
+		digits = strings.Repeat("0", int(decimals)-len(digits)+1) + digits
+	}
+	whole := digits[:len(digits)-int(decimals)]
+	frac := strings.TrimRight(digits[len(digits)-int(decimals):], "0")
+	out := whole
+	if frac != "" {
+		out += "." + frac
+	}
+	if negative {
+		out = "-" + out
+	}
+	return out
+}
@@ -0,0 +1,275 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+)
+
+// gRPC-gateway routes of the Exocore assets and delegation modules.
+const (
+	stakerAssetsPath       = "/exocore/assets/v1/QueStakerAssetInfos"
+	operatorAssetsPath     = "/exocore/assets/v1/QueOperatorAssetInfos"
+	stakingAssetInfoPath   = "/exocore/assets/v1/QueStakingAssetInfo"
+	associatedOperatorPath = "/exocore/delegation/v1/QueryAssociatedOperatorByStaker"
+)
+
+type stakerAssetInfo struct {
+	TotalDepositAmount        string `json:"total_deposit_amount"`
+	WithdrawableAmount        string `json:"withdrawable_amount"`
+	PendingUndelegationAmount string `json:"pending_undelegation_amount"`
+}
+
+type stakerAssetsResponse struct {
+	AssetInfos []struct {
+		AssetID string          `json:"asset_id"`
+		Info    stakerAssetInfo `json:"info"`
+	} `json:"asset_infos"`
+}
+
+type operatorAssetInfo struct {
+	TotalAmount               string `json:"total_amount"`
+	PendingUndelegationAmount string `json:"pending_undelegation_amount"`
+	TotalShare                string `json:"total_share"`
+	OperatorShare             string `json:"operator_share"`
+}
+
+type operatorAssetsResponse struct {
+	AssetInfos []struct {
+		AssetID string            `json:"asset_id"`
+		Info    operatorAssetInfo `json:"info"`
+	} `json:"asset_infos"`
+}
+
+type stakingAssetResponse struct {
+	AssetBasicInfo struct {
+		Name     string `json:"name"`
+		Symbol   string `json:"symbol"`
+		Address  string `json:"address"`
+		Decimals uint8  `json:"decimals"`
+	} `json:"asset_basic_info"`
+}
+
+type associatedOperatorResponse struct {
+	Operator string `json:"operator"`
+}
+
+// apiError is the error body of the gRPC gateway.
+type apiError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+var stakerAssetsCmd = &cobra.Command{
+	Use:   "staker-assets",
+	Short: "Show the deposited, withdrawable and undelegating amounts of a staker",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		apiUrl, _ := cmd.Flags().GetString("apiUrl")
+		staker, _ := cmd.Flags().GetString("staker")
+		err := stakerAssets_(apiUrl, staker)
+		if err != nil {
+			return failf("Failed to query staker assets: %v", err)
+		}
+		return nil
+	},
+}
+
+var operatorAssetsCmd = &cobra.Command{
+	Use:   "operator-assets",
+	Short: "Show the assets delegated to an operator",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		apiUrl, _ := cmd.Flags().GetString("apiUrl")
+		operator, _ := cmd.Flags().GetString("operator")
+		err := operatorAssets_(apiUrl, operator)
+		if err != nil {
+			return failf("Failed to query operator assets: %v", err)
+		}
+		return nil
+	},
+}
+
+var associatedOperatorCmd = &cobra.Command{
+	Use:   "associated-operator",
+	Short: "Show the operator a staker is associated with",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		apiUrl, _ := cmd.Flags().GetString("apiUrl")
+		staker, _ := cmd.Flags().GetString("staker")
+		err := associatedOperator_(apiUrl, staker)
+		if err != nil {
+			return failf("Failed to query associated operator: %v", err)
+		}
+		return nil
+	},
+}
+
+func registerAPIQueryCommands() {
+	queryCmd.AddCommand(stakerAssetsCmd)
+	queryCmd.AddCommand(operatorAssetsCmd)
+	queryCmd.AddCommand(associatedOperatorCmd)
+
+	stakerAssetsCmd.Flags().String("apiUrl", "http://localhost:1317", "Exocore REST API URL")
+	stakerAssetsCmd.Flags().String("staker", "", "Staker address, combined with --layerZeroID into the staker ID")
+
+	operatorAssetsCmd.Flags().String("apiUrl", "http://localhost:1317", "Exocore REST API URL")
+	operatorAssetsCmd.Flags().String("operator", "", "Operator address")
+
+	associatedOperatorCmd.Flags().String("apiUrl", "http://localhost:1317", "Exocore REST API URL")
+	associatedOperatorCmd.Flags().String("staker", "", "Staker address, combined with --layerZeroID into the staker ID")
+}
+
+// apiGet fetches path from the REST API and decodes the JSON body into out.
+func apiGet(apiUrl, path string, params url.Values, out interface{}) error {
+	endpoint := strings.TrimRight(apiUrl, "/") + path
+	if len(params) > 0 {
+		endpoint += "?" + params.Encode()
+	}
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Get(endpoint)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var apiErr apiError
+		if json.Unmarshal(body, &apiErr) == nil && apiErr.Message != "" {
+			return fmt.Errorf("%s: %s (code %d)", path, apiErr.Message, apiErr.Code)
+		}
+		return fmt.Errorf("%s: %s", path, resp.Status)
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("invalid response from %s: %v", path, err)
+	}
+	return nil
+}
+
+// assetFormatter prints raw asset amounts in token units, looking up and caching
+// each asset's symbol and decimals.
+type assetFormatter struct {
+	apiUrl string
+	assets map[string]*stakingAssetResponse
+}
+
+func newAssetFormatter(apiUrl string) *assetFormatter {
+	return &assetFormatter{apiUrl: apiUrl, assets: make(map[string]*stakingAssetResponse)}
+}
+
+func (f *assetFormatter) lookup(assetID string) *stakingAssetResponse {
+	if info, ok := f.assets[assetID]; ok {
+		return info
+	}
+	var info stakingAssetResponse
+	if err := apiGet(f.apiUrl, stakingAssetInfoPath, url.Values{"asset_id": {assetID}}, &info); err != nil {
+		fmt.Fprintf(textOut, "  could not get decimals of %s: %v\n", assetID, err)
+		f.assets[assetID] = nil
+		return nil
+	}
+	f.assets[assetID] = &info
+	return &info
+}
+
+// header describes assetID as e.g. "0xdac1..._0x65 (USDT, 6 decimals)".
+func (f *assetFormatter) header(assetID string) string {
+	info := f.lookup(assetID)
+	if info == nil {
+		return assetID
+	}
+	return fmt.Sprintf("%s (%s, %d decimals)", assetID, info.AssetBasicInfo.Symbol, info.AssetBasicInfo.Decimals)
+}
+
+// amount renders raw in token units followed by the raw value.
+func (f *assetFormatter) amount(assetID, raw string) string {
+	info := f.lookup(assetID)
+	value, ok := new(big.Int).SetString(raw, 10)
+	if info == nil || !ok {
+		return raw
+	}
+	return fmt.Sprintf("%s %s (%s)", formatUnits(value, info.AssetBasicInfo.Decimals), info.AssetBasicInfo.Symbol, raw)
+}
+
+func stakerIDFromFlag(staker string) (string, error) {
+	if !common.IsHexAddress(staker) {
+		return "", fmt.Errorf("invalid staker address: %q", staker)
+	}
+	return stakerID(common.HexToAddress(staker), layerZeroID), nil
+}
+
+func stakerAssets_(apiUrl, staker string) error {
+	id, err := stakerIDFromFlag(staker)
+	if err != nil {
+		return err
+	}
+	var resp stakerAssetsResponse
+	if err := apiGet(apiUrl, stakerAssetsPath, url.Values{"staker_id": {id}}, &resp); err != nil {
+		return err
+	}
+
+	setResult(map[string]interface{}{"stakerID": id, "assets": resp.AssetInfos})
+	fmt.Fprintln(textOut, "Staker", id)
+	if len(resp.AssetInfos) == 0 {
+		fmt.Fprintln(textOut, "  no deposits")
+	}
+	assets := newAssetFormatter(apiUrl)
+	for _, asset := range resp.AssetInfos {
+		fmt.Fprintln(textOut, " ", assets.header(asset.AssetID))
+		fmt.Fprintln(textOut, "    total deposit:       ", assets.amount(asset.AssetID, asset.Info.TotalDepositAmount))
+		fmt.Fprintln(textOut, "    withdrawable:        ", assets.amount(asset.AssetID, asset.Info.WithdrawableAmount))
+		fmt.Fprintln(textOut, "    pending undelegation:", assets.amount(asset.AssetID, asset.Info.PendingUndelegationAmount))
+	}
+	return nil
+}
+
+func operatorAssets_(apiUrl, operator string) error {
+	operator, err := normalizeOperator(operator)
+	if err != nil {
+		return err
+	}
+	var resp operatorAssetsResponse
+	if err := apiGet(apiUrl, operatorAssetsPath, url.Values{"operator_addr": {operator}}, &resp); err != nil {
+		return err
+	}
+
+	setResult(map[string]interface{}{"operator": operator, "assets": resp.AssetInfos})
+	fmt.Fprintln(textOut, "Operator", operator)
+	if len(resp.AssetInfos) == 0 {
+		fmt.Fprintln(textOut, "  no delegated assets")
+	}
+	assets := newAssetFormatter(apiUrl)
+	for _, asset := range resp.AssetInfos {
+		fmt.Fprintln(textOut, " ", assets.header(asset.AssetID))
+		fmt.Fprintln(textOut, "    total amount:        ", assets.amount(asset.AssetID, asset.Info.TotalAmount))
+		fmt.Fprintln(textOut, "    pending undelegation:", assets.amount(asset.AssetID, asset.Info.PendingUndelegationAmount))
+		fmt.Fprintln(textOut, "    total share:         ", asset.Info.TotalShare)
+		fmt.Fprintln(textOut, "    operator share:      ", asset.Info.OperatorShare)
+	}
+	return nil
+}
+
+func associatedOperator_(apiUrl, staker string) error {
+	id, err := stakerIDFromFlag(staker)
+	if err != nil {
+		return err
+	}
+	var resp associatedOperatorResponse
+	if err := apiGet(apiUrl, associatedOperatorPath, url.Values{"staker_id": {id}}, &resp); err != nil {
+		return err
+	}
+	setResult(map[string]interface{}{"stakerID": id, "operator": resp.Operator})
+	if resp.Operator == "" {
+		fmt.Fprintln(textOut, "Staker", id, "is not associated with an operator")
+		return nil
+	}
+	fmt.Fprintln(textOut, "Staker", id, "is associated with", resp.Operator)
+	return nil
+}
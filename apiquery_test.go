@@ -0,0 +1,151 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+const (
+	usdtAssetID = "0xdac17f958d2ee523a2206206994597c13d831ec7_0x65"
+	nstAssetID  = "0xeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee_0x65"
+)
+
+// recordedAPI serves the JSON responses recorded in testdata. Staking asset
+// info is only known for USDT, other assets get the gateway's not found error.
+func recordedAPI(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case stakerAssetsPath:
+			serveRecorded(t, w, "QueStakerAssetInfos.json")
+		case stakingAssetInfoPath:
+			if r.URL.Query().Get("asset_id") != usdtAssetID {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"code":5,"message":"asset not found","details":[]}`))
+				return
+			}
+			serveRecorded(t, w, "QueStakingAssetInfo.json")
+		default:
+			w.WriteHeader(http.StatusNotImplemented)
+		}
+	}))
+}
+
+func serveRecorded(t *testing.T, w http.ResponseWriter, name string) {
+	body, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Errorf("recorded response: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(body)
+}
+
+func TestStakerAssetsDecodesRecordedResponse(t *testing.T) {
+	server := recordedAPI(t)
+	defer server.Close()
+
+	var resp stakerAssetsResponse
+	if err := apiGet(server.URL, stakerAssetsPath, nil, &resp); err != nil {
+		t.Fatalf("apiGet: %v", err)
+	}
+	if len(resp.AssetInfos) != 2 {
+		t.Fatalf("got %d assets, want 2", len(resp.AssetInfos))
+	}
+	usdt := resp.AssetInfos[0]
+	if usdt.AssetID != usdtAssetID || usdt.Info.TotalDepositAmount != "1500000" || usdt.Info.WithdrawableAmount != "1000000" {
+		t.Fatalf("unexpected first asset %+v", usdt)
+	}
+}
+
+func TestAssetFormatterDecimals(t *testing.T) {
+	server := recordedAPI(t)
+	defer server.Close()
+
+	assets := newAssetFormatter(server.URL)
+	if got, want := assets.header(usdtAssetID), usdtAssetID+" (USDT, 6 decimals)"; got != want {
+		t.Errorf("header = %q, want %q", got, want)
+	}
+	tests := []struct {
+		raw, want string
+	}{
+		{"1500000", "1.5 USDT (1500000)"},
+		{"1000000", "1 USDT (1000000)"},
+		{"1", "0.000001 USDT (1)"},
+		{"0", "0 USDT (0)"},
+	}
+	for _, tt := range tests {
+		if got := assets.amount(usdtAssetID, tt.raw); got != tt.want {
+			t.Errorf("amount(%s) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+	if got := assets.amount(usdtAssetID, "not-a-number"); got != "not-a-number" {
+		t.Errorf("amount of a non-integer = %q, want it unchanged", got)
+	}
+}
+
+func TestAssetFormatterFallsBackToRawAmounts(t *testing.T) {
+	server := recordedAPI(t)
+	defer server.Close()
+
+	assets := newAssetFormatter(server.URL)
+	if got := assets.header(nstAssetID); got != nstAssetID {
+		t.Errorf("header = %q, want the bare asset ID", got)
+	}
+	if got := assets.amount(nstAssetID, "250000000000000000"); got != "250000000000000000" {
+		t.Errorf("amount = %q, want the raw amount", got)
+	}
+	if _, cached := assets.assets[nstAssetID]; !cached {
+		t.Errorf("failed lookup was not cached")
+	}
+}
+
+func TestAPIGetErrors(t *testing.T) {
+	server := recordedAPI(t)
+	defer server.Close()
+
+	var info stakingAssetResponse
+	err := apiGet(server.URL, stakingAssetInfoPath, nil, &info)
+	if err == nil || !strings.Contains(err.Error(), "asset not found (code 5)") {
+		t.Errorf("apiGet error = %v, want the gateway message and code", err)
+	}
+	err = apiGet(server.URL, operatorAssetsPath, nil, &info)
+	if err == nil || !strings.Contains(err.Error(), "501 Not Implemented") {
+		t.Errorf("apiGet error = %v, want the HTTP status", err)
+	}
+
+	garbage := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<html>proxy error</html>"))
+	}))
+	defer garbage.Close()
+	err = apiGet(garbage.URL, stakerAssetsPath, nil, &info)
+	if err == nil || !strings.Contains(err.Error(), "invalid response") {
+		t.Errorf("apiGet error = %v, want an invalid response error", err)
+	}
+}
+
+func TestStakerAssetsRejectsInvalidStaker(t *testing.T) {
+	if err := stakerAssets_("http://127.0.0.1:0", "0x1234"); err == nil {
+		t.Fatal("stakerAssets_ accepted an invalid staker address")
+	}
+}
+
+func TestStakerAssetsAgainstRecordedAPI(t *testing.T) {
+	server := recordedAPI(t)
+	defer server.Close()
+
+	setGlobal(t, &layerZeroID, 101)
+	if err := stakerAssets_(server.URL, "0x3e108c058e8066da635321dc3018294ca82ddedf"); err != nil {
+		t.Fatalf("stakerAssets_: %v", err)
+	}
+	result, ok := report.Result.(map[string]interface{})
+	if !ok || result["stakerID"] != "0x3e108c058e8066da635321dc3018294ca82ddedf_"+hexutil.EncodeUint64(uint64(layerZeroID)) {
+		t.Fatalf("unexpected result %v", report.Result)
+	}
+}
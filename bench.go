@@ -0,0 +1,438 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"math/rand"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// maxBenchTPS is the highest --tps whose send interval is still a nanosecond.
+const maxBenchTPS = 1e9
+
+// defaultBenchMix weighs the operations equally.
+var defaultBenchMix = map[string]uint{"deposit": 1, "delegate": 1, "undelegate": 1, "withdraw": 1}
+
+// benchAccount is a derived account sending bench traffic. mu keeps its sends
+// in nonce order.
+type benchAccount struct {
+	mu     sync.Mutex
+	signer Signer
+	nonces *nonceManager
+}
+
+// benchMethodStats are the counts and gas of one operation.
+type benchMethodStats struct {
+	Submitted  int    `json:"submitted"`
+	Mined      int    `json:"mined"`
+	Failed     int    `json:"failed"`
+	Errors     int    `json:"errors"`
+	Dropped    int    `json:"dropped"`
+	GasUsed    uint64 `json:"gasUsed"`
+	AvgGasUsed uint64 `json:"avgGasUsed"`
+}
+
+// benchReport is the outcome of a bench run. Submitted counts the transactions
+// the node accepted, Errors those it rejected or that failed gas estimation,
+// and Dropped the submitted ones without a receipt by --timeout.
+type benchReport struct {
+	Duration     string                       `json:"duration"`
+	Accounts     int                          `json:"accounts"`
+	TargetTPS    float64                      `json:"targetTps"`
+	SubmittedTPS float64                      `json:"submittedTps"`
+	MinedTPS     float64                      `json:"minedTps"`
+	Submitted    int                          `json:"submitted"`
+	Mined        int                          `json:"mined"`
+	Failed       int                          `json:"failed"`
+	Errors       int                          `json:"errors"`
+	Dropped      int                          `json:"dropped"`
+	LatencyMs    map[string]float64           `json:"latencyMs,omitempty"`
+	Methods      map[string]*benchMethodStats `json:"methods"`
+	LastError    string                       `json:"lastError,omitempty"`
+}
+
+var benchCmd = &cobra.Command{
+	Use:   "bench",
+	Short: "Send randomized deposit, delegate, undelegate and withdraw traffic from derived accounts at a target TPS",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rpcUrl, _ := cmd.Flags().GetString("rpcUrl")
+		accounts, _ := cmd.Flags().GetUint32("accounts")
+		tps, _ := cmd.Flags().GetFloat64("tps")
+		duration, _ := cmd.Flags().GetDuration("duration")
+		mixFile, _ := cmd.Flags().GetString("mix")
+		operators, _ := cmd.Flags().GetStringSlice("operators")
+		minAmount, _ := cmd.Flags().GetString("min-amount")
+		maxAmount, _ := cmd.Flags().GetString("max-amount")
+		pollInterval, _ := cmd.Flags().GetDuration("poll-interval")
+		seed, _ := cmd.Flags().GetInt64("seed")
+		err := bench_(rpcUrl, accounts, tps, duration, mixFile, operators, minAmount, maxAmount, pollInterval, seed)
+		if err != nil {
+			return failf("Failed to run bench: %v", err)
+		}
+		return nil
+	},
+}
+
+func registerBenchCommands() {
+	rootCmd.AddCommand(benchCmd)
+
+	benchCmd.Flags().String("rpcUrl", "http://localhost:8545", "Exocore RPC URL")
+	benchCmd.Flags().Uint32("accounts", 10, "Number of accounts derived from --mnemonic-file, starting at --account-index")
+	benchCmd.Flags().Float64("tps", 10, "Target transactions per second")
+	benchCmd.Flags().Duration("duration", time.Minute, "How long to send traffic")
+	benchCmd.Flags().String("mix", "", "YAML or JSON file of operation weights, e.g. {deposit: 5, delegate: 3, undelegate: 1, withdraw: 1} (default equal weights)")
+	benchCmd.Flags().StringSlice("operators", nil, "Operators to delegate to and undelegate from, picked at random")
+	benchCmd.Flags().String("min-amount", "1", "Smallest random amount")
+	benchCmd.Flags().String("max-amount", "1000", "Largest random amount")
+	benchCmd.Flags().Duration("poll-interval", 200*time.Millisecond, "How often to poll for receipts")
+	benchCmd.Flags().Int64("seed", 0, "Random seed, 0 for a new one each run")
+	requireGlobalFlags(benchCmd, "defaultAssetID")
+}
+
+// loadBenchMix reads the operation weights of path, or the default mix.
+func loadBenchMix(path string) (map[string]uint, error) {
+	if path == "" {
+		return defaultBenchMix, nil
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	mix := make(map[string]uint)
+	if err := yaml.Unmarshal(raw, &mix); err != nil {
+		return nil, fmt.Errorf("invalid mix %s: %v", path, err)
+	}
+	total := uint(0)
+	for name, weight := range mix {
+		if _, ok := bulkOps[name]; !ok {
+			return nil, fmt.Errorf("mix %s: unknown operation %q, expected deposit, delegate, undelegate or withdraw", path, name)
+		}
+		total += weight
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("mix %s has no operation with a weight", path)
+	}
+	return mix, nil
+}
+
+// pickWeighted returns an operation of mix with probability proportional to its weight.
+func pickWeighted(rng *rand.Rand, names []string, mix map[string]uint, total uint) string {
+	n := uint(rng.Int63n(int64(total)))
+	for _, name := range names {
+		if n < mix[name] {
+			return name
+		}
+		n -= mix[name]
+	}
+	return names[len(names)-1]
+}
+
+func deriveBenchAccounts(client *ethclient.Client, chainID *big.Int, count uint32) ([]*benchAccount, error) {
+	if mnemonicFile == "" {
+		return nil, fmt.Errorf("--mnemonic-file is required to derive the bench accounts")
+	}
+	if err := checkAccountRange(count); err != nil {
+		return nil, err
+	}
+	mnemonic, err := readMnemonic(mnemonicFile)
+	if err != nil {
+		return nil, err
+	}
+	accounts := make([]*benchAccount, 0, count)
+	for n := uint32(0); n < count; n++ {
+		sk, _, err := deriveKey(mnemonic, hdPath, accountIndex+n)
+		if err != nil {
+			return nil, err
+		}
+		signer := newKeySigner(sk)
+		accounts = append(accounts, &benchAccount{signer: signer, nonces: nonceManagerFor(client, chainID, signer.Address())})
+	}
+	return accounts, nil
+}
+
+func bench_(rpcUrl string, accountCount uint32, tps float64, duration time.Duration, mixFile string, operators []string, minAmountStr, maxAmountStr string, pollInterval time.Duration, seed int64) error {
+	if accountCount == 0 || duration <= 0 || pollInterval <= 0 {
+		return withCode(errCodeInvalidArguments, fmt.Errorf("--accounts, --duration and --poll-interval must be positive"))
+	}
+	// one tick per nanosecond is the ticker's limit
+	if !(tps > 0 && tps <= maxBenchTPS) {
+		return withCode(errCodeInvalidArguments, fmt.Errorf("--tps must be above 0 and at most %g, got %v", maxBenchTPS, tps))
+	}
+	mix, err := loadBenchMix(mixFile)
+	if err != nil {
+		return withCode(errCodeInvalidArguments, err)
+	}
+	var names []string
+	total := uint(0)
+	for name, weight := range mix {
+		if weight == 0 {
+			continue
+		}
+		if bulkOps[name].operator && len(operators) == 0 {
+			return withCode(errCodeInvalidArguments, fmt.Errorf("--operators is required to %s", name))
+		}
+		names = append(names, name)
+		total += weight
+	}
+	sort.Strings(names)
+	for i, operator := range operators {
+		normalized, err := normalizeOperator(operator)
+		if err != nil {
+			return withCode(errCodeInvalidArguments, fmt.Errorf("--operators: %v", err))
+		}
+		operators[i] = normalized
+	}
+	asset := lookupAsset(defaultAssetID, layerZeroID)
+	minAmount, err := parseAmount(minAmountStr, asset)
+	if err != nil {
+		return err
+	}
+	maxAmount, err := parseAmount(maxAmountStr, asset)
+	if err != nil {
+		return err
+	}
+	if minAmount.Sign() <= 0 || maxAmount.Cmp(minAmount) < 0 {
+		return withCode(errCodeInvalidArguments, fmt.Errorf("amounts must satisfy 0 < --min-amount <= --max-amount"))
+	}
+	span := new(big.Int).Add(new(big.Int).Sub(maxAmount, minAmount), big.NewInt(1))
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	rng := rand.New(rand.NewSource(seed))
+
+	_, ethClient, err := connectToEthereum(rpcUrl)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	chainID, err := ethClient.ChainID(ctx)
+	if err != nil {
+		return err
+	}
+	accounts, err := deriveBenchAccounts(ethClient, chainID, accountCount)
+	if err != nil {
+		return withCode(errCodeSigner, err)
+	}
+	fees, err := resolveFees(ctx, ethClient)
+	if err != nil {
+		return err
+	}
+
+	stats := make(map[string]*benchMethodStats, len(names))
+	for _, name := range names {
+		stats[name] = &benchMethodStats{}
+	}
+	result := &benchReport{Accounts: len(accounts), TargetTPS: tps, Methods: stats}
+	var (
+		mu        sync.Mutex
+		latencies []time.Duration
+		wg        sync.WaitGroup
+	)
+	fail := func(name string, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		stats[name].Errors++
+		result.Errors++
+		result.LastError = err.Error()
+	}
+
+	// send signs and sends one transaction, then polls for its receipt
+	send := func(name string, account *benchAccount, to common.Address, data []byte, fees txFees) {
+		defer wg.Done()
+		from := account.signer.Address()
+		gasLimit, err := resolveGasLimit(ctx, ethClient, ethereum.CallMsg{From: from, To: &to, Data: data})
+		if err != nil {
+			fail(name, err)
+			return
+		}
+
+		account.mu.Lock()
+		nonce, err := account.nonces.Next(ctx)
+		if err != nil {
+			account.mu.Unlock()
+			fail(name, err)
+			return
+		}
+		signed, err := account.signer.SignTx(newTx(chainID, nonce, to, gasLimit, fees, data), chainID)
+		if err == nil {
+			err = ethClient.SendTransaction(ctx, signed)
+		}
+		sentAt := time.Now()
+		if err != nil {
+			if isNonceError(err) {
+				account.nonces.Resync()
+			} else {
+				account.nonces.Release(nonce)
+			}
+		}
+		account.mu.Unlock()
+		if err != nil {
+			fail(name, err)
+			return
+		}
+		mu.Lock()
+		stats[name].Submitted++
+		result.Submitted++
+		mu.Unlock()
+
+		receipt, err := pollReceipt(ctx, ethClient, signed.Hash(), pollInterval)
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			stats[name].Dropped++
+			result.Dropped++
+			result.LastError = err.Error()
+			return
+		}
+		latency := time.Since(sentAt)
+		latencies = append(latencies, latency)
+		stats[name].GasUsed += receipt.GasUsed
+		if receipt.Status == types.ReceiptStatusSuccessful {
+			stats[name].Mined++
+			result.Mined++
+		} else {
+			stats[name].Failed++
+			result.Failed++
+		}
+	}
+
+	fmt.Fprintf(textOut, "Sending %s to %s at %v tx/s for %s from %d accounts (seed %d)\n", strings.Join(names, ", "), rpcUrl, tps, duration, len(accounts), seed)
+	interval := time.Duration(float64(time.Second) / tps)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	progress := time.NewTicker(5 * time.Second)
+	defer progress.Stop()
+	start := time.Now()
+	deadline := time.After(duration)
+	feesAt := start
+	var packErr error
+sending:
+	for {
+		select {
+		case <-deadline:
+			break sending
+		case <-progress.C:
+			mu.Lock()
+			fmt.Fprintf(textOut, "%s: submitted %d, mined %d, failed %d, errors %d, dropped %d\n", time.Since(start).Round(time.Second), result.Submitted, result.Mined, result.Failed, result.Errors, result.Dropped)
+			mu.Unlock()
+		case <-ticker.C:
+			if time.Since(feesAt) > 5*time.Second {
+				if refreshed, err := resolveFees(ctx, ethClient); err == nil {
+					fees = refreshed
+				}
+				feesAt = time.Now()
+			}
+			name := pickWeighted(rng, names, mix, total)
+			op := bulkOps[name]
+			account := accounts[rng.Intn(len(accounts))]
+			operator := ""
+			if op.operator {
+				operator = operators[rng.Intn(len(operators))]
+			}
+			amount := new(big.Int).Add(minAmount, new(big.Int).Rand(rng, span))
+			_, data, err := op.pack(layerZeroID, defaultAssetID, account.signer.Address().Hex(), operator, amount)
+			if err != nil {
+				packErr = err
+				break sending
+			}
+			wg.Add(1)
+			go send(name, account, common.HexToAddress(op.to()), data, fees)
+		}
+	}
+	sendTime := time.Since(start)
+	fmt.Fprintln(textOut, "Waiting for outstanding receipts")
+	wg.Wait()
+	if packErr != nil {
+		return packErr
+	}
+
+	result.Duration = sendTime.Round(time.Millisecond).String()
+	result.SubmittedTPS = float64(result.Submitted) / sendTime.Seconds()
+	result.MinedTPS = float64(result.Mined) / time.Since(start).Seconds()
+	for _, s := range stats {
+		if n := s.Mined + s.Failed; n > 0 {
+			s.AvgGasUsed = s.GasUsed / uint64(n)
+		}
+	}
+	if len(latencies) > 0 {
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+		result.LatencyMs = map[string]float64{
+			"p50": percentileMs(latencies, 0.50),
+			"p90": percentileMs(latencies, 0.90),
+			"p99": percentileMs(latencies, 0.99),
+			"max": percentileMs(latencies, 1),
+		}
+	}
+	printBenchReport(result, names)
+	setResult(result)
+	return nil
+}
+
+// pollReceipt polls for the receipt of hash until --timeout. The error names
+// the last failed lookup, if any, so a node that errors is told from a slow one.
+func pollReceipt(ctx context.Context, client *ethclient.Client, hash common.Hash, interval time.Duration) (*types.Receipt, error) {
+	ctx, cancel := context.WithTimeout(ctx, waitTimeout)
+	defer cancel()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	var lookupErr error
+	for {
+		receipt, err := client.TransactionReceipt(ctx, hash)
+		if err == nil {
+			return receipt, nil
+		}
+		if !errors.Is(err, ethereum.NotFound) && ctx.Err() == nil {
+			debugf("Receipt of %s: %v\n", hash.Hex(), err)
+			lookupErr = err
+		}
+		select {
+		case <-ctx.Done():
+			if lookupErr != nil {
+				return nil, fmt.Errorf("no receipt for %s within %s: %v", hash.Hex(), waitTimeout, lookupErr)
+			}
+			return nil, fmt.Errorf("no receipt for %s within %s", hash.Hex(), waitTimeout)
+		case <-ticker.C:
+		}
+	}
+}
+
+// percentileMs returns the q quantile of sorted latencies in milliseconds.
+func percentileMs(sorted []time.Duration, q float64) float64 {
+	i := int(math.Ceil(q*float64(len(sorted)))) - 1
+	if i < 0 {
+		i = 0
+	}
+	return float64(sorted[i].Microseconds()) / 1000
+}
+
+func printBenchReport(r *benchReport, names []string) {
+	fmt.Fprintln(textOut, "Bench results:")
+	fmt.Fprintf(textOut, "  duration:   %s, %d accounts\n", r.Duration, r.Accounts)
+	fmt.Fprintf(textOut, "  throughput: target %.2f tx/s, submitted %.2f tx/s, mined %.2f tx/s\n", r.TargetTPS, r.SubmittedTPS, r.MinedTPS)
+	fmt.Fprintf(textOut, "  submitted %d, mined %d, failed %d, errors %d, dropped %d\n", r.Submitted, r.Mined, r.Failed, r.Errors, r.Dropped)
+	if r.LatencyMs != nil {
+		fmt.Fprintf(textOut, "  latency:    p50 %.1fms, p90 %.1fms, p99 %.1fms, max %.1fms\n", r.LatencyMs["p50"], r.LatencyMs["p90"], r.LatencyMs["p99"], r.LatencyMs["max"])
+	}
+	fmt.Fprintf(textOut, "  %-12s %9s %7s %7s %7s %7s %12s\n", "method", "submitted", "mined", "failed", "errors", "dropped", "avg gas")
+	for _, name := range names {
+		s := r.Methods[name]
+		fmt.Fprintf(textOut, "  %-12s %9d %7d %7d %7d %7d %12d\n", name, s.Submitted, s.Mined, s.Failed, s.Errors, s.Dropped, s.AvgGasUsed)
+	}
+	if r.LastError != "" {
+		fmt.Fprintln(textOut, "  last error:", r.LastError)
+	}
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"encoding/hex"
+	"math/big"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	benchTestMnemonic = "test test test test test test test test test test test junk"
+	benchTestAsset    = "0xdac17f958d2ee523a2206206994597c13d831ec7"
+	benchTestOperator = "0x00000000000000000000000000000000000000aa"
+)
+
+// benchNode is an in-process stand-in for the eth_ JSON-RPC methods bench
+// uses. Transactions are mined as soon as they are sent, with the gas of their
+// selector in gasBySelector; selectors in failing revert and those in dropping
+// are accepted but never mined.
+type benchNode struct {
+	chainID       *big.Int
+	gasBySelector map[string]uint64
+	failing       map[string]bool
+	dropping      map[string]bool
+
+	mu       sync.Mutex
+	receipts map[common.Hash]*types.Receipt
+	sent     map[string]int
+}
+
+func (n *benchNode) ChainId() *hexutil.Big {
+	return (*hexutil.Big)(n.chainID)
+}
+
+func (n *benchNode) GetTransactionCount(address common.Address, block string) hexutil.Uint64 {
+	return 0
+}
+
+func (n *benchNode) EstimateGas(args map[string]interface{}, block *string) hexutil.Uint64 {
+	return 100000
+}
+
+func (n *benchNode) SendRawTransaction(raw hexutil.Bytes) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(raw); err != nil {
+		return common.Hash{}, err
+	}
+	selector := hex.EncodeToString(tx.Data()[:4])
+	status := types.ReceiptStatusSuccessful
+	if n.failing[selector] {
+		status = types.ReceiptStatusFailed
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.sent[selector]++
+	if n.dropping[selector] {
+		return tx.Hash(), nil
+	}
+	n.receipts[tx.Hash()] = &types.Receipt{
+		Status:            status,
+		CumulativeGasUsed: n.gasBySelector[selector],
+		GasUsed:           n.gasBySelector[selector],
+		Logs:              []*types.Log{},
+		TxHash:            tx.Hash(),
+		BlockNumber:       big.NewInt(1),
+		BlockHash:         common.HexToHash("0x01"),
+	}
+	return tx.Hash(), nil
+}
+
+func (n *benchNode) GetTransactionReceipt(hash common.Hash) *types.Receipt {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.receipts[hash]
+}
+
+// setGlobal sets a flag variable for the duration of the test.
+func setGlobal[T any](t *testing.T, variable *T, value T) {
+	saved := *variable
+	*variable = value
+	t.Cleanup(func() { *variable = saved })
+}
+
+// benchSelector is the 4-byte selector, in hex, of the calldata op packs.
+func benchSelector(t *testing.T, name string) string {
+	operator, err := addressToBech32("exo", common.HexToAddress(benchTestOperator))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, data, err := bulkOps[name].pack(101, benchTestAsset, benchTestOperator, operator, big.NewInt(1))
+	if err != nil {
+		t.Fatalf("pack %s: %v", name, err)
+	}
+	return hex.EncodeToString(data[:4])
+}
+
+func TestBenchAgainstStubNode(t *testing.T) {
+	dir := t.TempDir()
+	mnemonicPath := filepath.Join(dir, "mnemonic.txt")
+	if err := os.WriteFile(mnemonicPath, []byte(benchTestMnemonic), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	setGlobal(t, &mnemonicFile, mnemonicPath)
+	setGlobal(t, &hdPath, ethHDPath)
+	setGlobal(t, &accountIndex, 0)
+	setGlobal(t, &defaultAssetID, benchTestAsset)
+	setGlobal(t, &layerZeroID, 101)
+	setGlobal(t, &registryFile, filepath.Join(dir, "registry.json"))
+	setGlobal(t, &txType, txTypeLegacy)
+	setGlobal(t, &gasPrice, "1000000000")
+	setGlobal(t, &gasMultiplier, 1)
+	setGlobal(t, &waitTimeout, time.Second)
+	setGlobal(t, &bech32HRP, "exo")
+
+	gas := map[string]uint64{"deposit": 60000, "delegate": 80000, "undelegate": 90000, "withdraw": 70000}
+	node := &benchNode{
+		chainID:       big.NewInt(232),
+		gasBySelector: make(map[string]uint64),
+		failing:       map[string]bool{benchSelector(t, "withdraw"): true},
+		dropping:      map[string]bool{benchSelector(t, "undelegate"): true},
+		receipts:      make(map[common.Hash]*types.Receipt),
+		sent:          make(map[string]int),
+	}
+	for name, used := range gas {
+		node.gasBySelector[benchSelector(t, name)] = used
+	}
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", node); err != nil {
+		t.Fatal(err)
+	}
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	operators := []string{benchTestOperator}
+	err := bench_(httpServer.URL, 3, 200, 300*time.Millisecond, "", operators, "1", "1000", 5*time.Millisecond, 42)
+	if err != nil {
+		t.Fatalf("bench_: %v", err)
+	}
+	result, ok := report.Result.(*benchReport)
+	if !ok {
+		t.Fatalf("unexpected result %T", report.Result)
+	}
+
+	total := 0
+	for _, count := range node.sent {
+		total += count
+	}
+	if total == 0 {
+		t.Fatal("no transaction reached the node")
+	}
+	withdrawals := node.sent[benchSelector(t, "withdraw")]
+	undelegations := node.sent[benchSelector(t, "undelegate")]
+	if result.Submitted != total || result.Mined != total-withdrawals-undelegations || result.Failed != withdrawals || result.Dropped != undelegations {
+		t.Errorf("submitted %d, mined %d, failed %d, dropped %d; node got %d with %d withdrawals and %d undelegations", result.Submitted, result.Mined, result.Failed, result.Dropped, total, withdrawals, undelegations)
+	}
+	if result.Errors != 0 {
+		t.Errorf("errors %d, want none (last error %q)", result.Errors, result.LastError)
+	}
+	if undelegations > 0 && !strings.Contains(result.LastError, "no receipt") {
+		t.Errorf("last error %q, want the missing receipt", result.LastError)
+	}
+	for name, used := range gas {
+		stats := result.Methods[name]
+		count := node.sent[benchSelector(t, name)]
+		if stats.Submitted != count {
+			t.Errorf("%s: submitted %d, node got %d", name, stats.Submitted, count)
+		}
+		if node.dropping[benchSelector(t, name)] {
+			if stats.Dropped != count || stats.GasUsed != 0 {
+				t.Errorf("%s: dropped %d with %d gas, want %d without gas", name, stats.Dropped, stats.GasUsed, count)
+			}
+			continue
+		}
+		if stats.Dropped != 0 {
+			t.Errorf("%s: dropped %d, want none", name, stats.Dropped)
+		}
+		if stats.GasUsed != used*uint64(count) {
+			t.Errorf("%s: gas used %d, want %d", name, stats.GasUsed, used*uint64(count))
+		}
+		if count > 0 && stats.AvgGasUsed != used {
+			t.Errorf("%s: average gas %d, want %d", name, stats.AvgGasUsed, used)
+		}
+	}
+	if result.LatencyMs == nil {
+		t.Error("no latency percentiles")
+	}
+}
+
+func TestBenchRejectsOutOfRangeArguments(t *testing.T) {
+	tests := []struct {
+		name     string
+		accounts uint32
+		tps      float64
+		want     string
+	}{
+		{"tps above one per nanosecond", 1, 2e9, "--tps"},
+		{"zero tps", 1, 0, "--tps"},
+		{"no accounts", 0, 10, "--accounts"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := bench_("http://127.0.0.1:0", tt.accounts, tt.tps, time.Second, "", nil, "1", "2", time.Second, 1)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("bench_ error = %v, want it to mention %s", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+var (
+	buildOnly   bool
+	buildFormat string
+)
+
+// unsignedTx is the --build-only json output, enough for a multisig to propose the call.
+type unsignedTx struct {
+	Label  string         `json:"label"`
+	To     common.Address `json:"to"`
+	Value  string         `json:"value"`
+	Data   hexutil.Bytes  `json:"data"`
+	Method string         `json:"method"`
+	Args   []decodedArg   `json:"args"`
+}
+
+// safeBatch is the Safe Transaction Builder batch file format.
+type safeBatch struct {
+	Version      string            `json:"version"`
+	ChainID      string            `json:"chainId"`
+	CreatedAt    int64             `json:"createdAt"`
+	Meta         safeBatchMeta     `json:"meta"`
+	Transactions []safeTransaction `json:"transactions"`
+}
+
+type safeBatchMeta struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type safeTransaction struct {
+	To                   common.Address    `json:"to"`
+	Value                string            `json:"value"`
+	Data                 hexutil.Bytes     `json:"data"`
+	ContractMethod       interface{}       `json:"contractMethod"`
+	ContractInputsValues map[string]string `json:"contractInputsValues"`
+}
+
+func registerBuildOnlyFlags() {
+	rootCmd.PersistentFlags().BoolVar(&buildOnly, "build-only", false, "Print the unsigned call (precompile, calldata, decoded args) instead of sending it")
+	rootCmd.PersistentFlags().StringVar(&buildFormat, "build-format", "json", "Output format of --build-only: json or safe (Safe Transaction Builder batch, appended to --out)")
+}
+
+// buildUnsigned emits the call for data without signing it. Nothing is dialed
+// except to look up the chain ID of a Safe batch when --chain-id is not set.
+func buildUnsigned(rpcUrl, label string, to common.Address, contractAbi abi.ABI, data []byte) error {
+	method, args, err := decodeCallArgs(contractAbi, data)
+	if err != nil {
+		return err
+	}
+	writeOut := rootCmd.PersistentFlags().Changed("out")
+	report.Status = "built"
+
+	switch buildFormat {
+	case "json":
+		out, err := json.MarshalIndent(unsignedTx{
+			Label:  label,
+			To:     to,
+			Value:  "0",
+			Data:   data,
+			Method: method.Sig,
+			Args:   args,
+		}, "", "  ")
+		if err != nil {
+			return err
+		}
+		if !writeOut {
+			fmt.Fprintln(textOut, string(out))
+			return nil
+		}
+		if err := os.WriteFile(offlineOut, out, 0o644); err != nil {
+			return err
+		}
+	case "safe":
+		batch, err := loadSafeBatch(rpcUrl, writeOut)
+		if err != nil {
+			return err
+		}
+		batch.Transactions = append(batch.Transactions, safeTransaction{
+			To:    to,
+			Value: "0",
+			Data:  data,
+		})
+		batch.Meta.Description = appendDescription(batch.Meta.Description, method.Sig)
+		out, err := json.MarshalIndent(batch, "", "  ")
+		if err != nil {
+			return err
+		}
+		if !writeOut {
+			fmt.Fprintln(textOut, string(out))
+			return nil
+		}
+		if err := os.WriteFile(offlineOut, out, 0o644); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown --build-format %q, expected json or safe", buildFormat)
+	}
+
+	printDecodedArgs(method, args)
+	fmt.Fprintln(textOut, label, "call written to", offlineOut)
+	setResult(map[string]string{"out": offlineOut})
+	return nil
+}
+
+// loadSafeBatch reads the batch in --out so repeated calls accumulate into one proposal.
+func loadSafeBatch(rpcUrl string, fromFile bool) (*safeBatch, error) {
+	if fromFile {
+		content, err := os.ReadFile(offlineOut)
+		if err == nil {
+			batch := new(safeBatch)
+			if err := json.Unmarshal(content, batch); err != nil {
+				return nil, fmt.Errorf("%s is not a Safe batch file: %v", offlineOut, err)
+			}
+			return batch, nil
+		}
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+
+	chainID := offlineChainID
+	if chainID == 0 {
+		_, ethClient, err := connectToEthereum(rpcUrl)
+		if err != nil {
+			return nil, err
+		}
+		id, err := ethClient.ChainID(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("failed to get chain ID, pass --chain-id: %v", err)
+		}
+		chainID = id.Uint64()
+	}
+	return &safeBatch{
+		Version:   "1.0",
+		ChainID:   strconv.FormatUint(chainID, 10),
+		CreatedAt: time.Now().UnixMilli(),
+		Meta:      safeBatchMeta{Name: "assetcli batch"},
+	}, nil
+}
+
+func appendDescription(description, sig string) string {
+	if description == "" {
+		return sig
+	}
+	return description + "; " + sig
+}
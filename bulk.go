@@ -0,0 +1,518 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+)
+
+const (
+	rowConfirmed = "confirmed"
+	rowSent      = "sent"
+	rowFailed    = "failed"
+	rowError     = "error"
+	rowSimulated = "simulated"
+)
+
+// bulkColumns are the columns of a bulk CSV, staker and amount being required.
+var bulkColumns = []string{"staker", "amount", "operator", "asset", "chain"}
+
+// bulkResultColumns are the columns of the results CSV.
+var bulkResultColumns = []string{"row", "staker", "amount", "operator", "asset", "chain", "txHash", "status", "error"}
+
+// bulkRow is a row of a bulk CSV and its outcome. Asset and chain are resolved
+// to an address and a LayerZero ID once the row is checked.
+type bulkRow struct {
+	Row      int    `json:"row"`
+	Staker   string `json:"staker"`
+	Amount   string `json:"amount"`
+	Operator string `json:"operator,omitempty"`
+	Asset    string `json:"asset"`
+	Chain    string `json:"chain"`
+	TxHash   string `json:"txHash,omitempty"`
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+
+	to   common.Address
+	abi  abi.ABI
+	data []byte
+	// occurrence counts the earlier rows with the same content
+	occurrence int
+}
+
+// key identifies a row across runs by its content, so an edited row is not
+// taken as done and inserting or moving lines does not make rows look new.
+// Identical rows are told apart by their occurrence.
+func (r *bulkRow) key() string {
+	return r.content() + "|" + strconv.Itoa(r.occurrence)
+}
+
+func (r *bulkRow) content() string {
+	return strings.Join([]string{strings.ToLower(r.Staker), r.Amount, r.Operator, strings.ToLower(r.Asset), r.Chain}, "|")
+}
+
+// numberOccurrences sets the occurrence of each row, in order.
+func numberOccurrences(rows []*bulkRow) {
+	seen := make(map[string]int, len(rows))
+	for _, row := range rows {
+		content := row.content()
+		row.occurrence = seen[content]
+		seen[content]++
+	}
+}
+
+// bulkOp is what a bulk subcommand sends for each row.
+type bulkOp struct {
+	label    string
+	operator bool
+	to       func() string
+	pack     func(clientChainID uint32, assetID, stakerAddress, operator string, amount *big.Int) (abi.ABI, []byte, error)
+}
+
+var bulkInstantUnbond bool
+
+var bulkOps = map[string]bulkOp{
+	"deposit": {
+		label: "Deposit",
+		to:    func() string { return depositPrecompileAddress },
+		pack: func(clientChainID uint32, assetID, stakerAddress, _ string, amount *big.Int) (abi.ABI, []byte, error) {
+			return depositLSTCall(clientChainID, assetID, stakerAddress, amount)
+		},
+	},
+	"delegate": {
+		label:    "Delegate To",
+		operator: true,
+		to:       func() string { return delegatePrecompileAddress },
+		pack:     delegateCall,
+	},
+	"undelegate": {
+		label:    "Undelegate",
+		operator: true,
+		to:       func() string { return delegatePrecompileAddress },
+		pack: func(clientChainID uint32, assetID, stakerAddress, operator string, amount *big.Int) (abi.ABI, []byte, error) {
+			return undelegateCall(clientChainID, assetID, stakerAddress, operator, amount, bulkInstantUnbond)
+		},
+	},
+	"withdraw": {
+		label: "Withdraw LST",
+		to:    func() string { return depositPrecompileAddress },
+		pack: func(clientChainID uint32, assetID, stakerAddress, _ string, amount *big.Int) (abi.ABI, []byte, error) {
+			return withdrawLSTCall(clientChainID, assetID, stakerAddress, amount)
+		},
+	},
+}
+
+var bulkCmd = &cobra.Command{
+	Use:   "bulk",
+	Short: "Send a deposit, delegate, undelegate or withdraw for every row of a CSV file",
+}
+
+func registerBulkCommands() {
+	rootCmd.AddCommand(bulkCmd)
+
+	for _, name := range []string{"deposit", "delegate", "undelegate", "withdraw"} {
+		op := bulkOps[name]
+		columns := "staker, amount, asset and chain"
+		if op.operator {
+			columns = "staker, operator, amount, asset and chain"
+		}
+		cmd := &cobra.Command{
+			Use:   name,
+			Short: fmt.Sprintf("%s for every row (%s) of --csv", op.label, columns),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				rpcUrl, _ := cmd.Flags().GetString("rpcUrl")
+				csvFile, _ := cmd.Flags().GetString("csv")
+				resultsFile, _ := cmd.Flags().GetString("results")
+				concurrency, _ := cmd.Flags().GetInt("concurrency")
+				err := bulk_(rpcUrl, csvFile, resultsFile, concurrency, op)
+				if err != nil {
+					return failf("Failed to run bulk %s: %v", cmd.Name(), err)
+				}
+				return nil
+			},
+		}
+		cmd.Flags().String("rpcUrl", "http://localhost:8545", "Exocore RPC URL")
+		cmd.Flags().String("csv", "", "CSV file with a header row naming the columns staker, amount, operator, asset and chain")
+		cmd.Flags().String("results", "", "Results CSV, rows confirmed in it are skipped (default <csv>.results.csv)")
+		cmd.Flags().Int("concurrency", 4, "Maximum number of transactions in flight")
+		if name == "undelegate" {
+			cmd.Flags().BoolVar(&bulkInstantUnbond, "instantUnbond", false, "Instant unbond")
+		}
+		if err := cmd.MarkFlagRequired("csv"); err != nil {
+			panic(err)
+		}
+		bulkCmd.AddCommand(cmd)
+	}
+}
+
+// readBulkCSV reads the rows of path, the first line naming the columns.
+func readBulkCSV(path string) ([]*bulkRow, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("%s is empty", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	index := make(map[string]int, len(header))
+	for i, column := range header {
+		column = strings.ToLower(strings.TrimSpace(column))
+		known := false
+		for _, name := range bulkColumns {
+			known = known || name == column
+		}
+		if !known {
+			return nil, fmt.Errorf("%s: unknown column %q, expected %s", path, column, strings.Join(bulkColumns, ", "))
+		}
+		index[column] = i
+	}
+	for _, name := range []string{"staker", "amount"} {
+		if _, ok := index[name]; !ok {
+			return nil, fmt.Errorf("%s: missing column %s", path, name)
+		}
+	}
+
+	var rows []*bulkRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		field := func(name string) string {
+			if i, ok := index[name]; ok {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		rows = append(rows, &bulkRow{
+			Row:      len(rows) + 1,
+			Staker:   field("staker"),
+			Amount:   field("amount"),
+			Operator: field("operator"),
+			Asset:    field("asset"),
+			Chain:    field("chain"),
+		})
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%s has no rows", path)
+	}
+	return rows, nil
+}
+
+// loadBulkResults reads a previous results CSV by row key, an absent file
+// being a first run.
+func loadBulkResults(path string) (map[string]*bulkRow, error) {
+	previous := make(map[string]*bulkRow)
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return previous, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	var rows []*bulkRow
+	for i, record := range records {
+		if i == 0 || len(record) != len(bulkResultColumns) {
+			continue
+		}
+		n, err := strconv.Atoi(record[0])
+		if err != nil {
+			return nil, fmt.Errorf("%s line %d: invalid row %q", path, i+1, record[0])
+		}
+		rows = append(rows, &bulkRow{Row: n, Staker: record[1], Amount: record[2], Operator: record[3], Asset: record[4], Chain: record[5], TxHash: record[6], Status: record[7], Error: record[8]})
+	}
+	numberOccurrences(rows)
+	for _, row := range rows {
+		previous[row.key()] = row
+	}
+	return previous, nil
+}
+
+// writeBulkResults replaces path with the rows, through a temporary file so an
+// interrupted run leaves the previous results intact.
+func writeBulkResults(path string, rows []*bulkRow) error {
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	writer := csv.NewWriter(f)
+	_ = writer.Write(bulkResultColumns)
+	for _, row := range rows {
+		_ = writer.Write([]string{strconv.Itoa(row.Row), row.Staker, row.Amount, row.Operator, row.Asset, row.Chain, row.TxHash, row.Status, row.Error})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// prepareBulkRow resolves the row's chain and asset, checks its fields and
+// packs its calldata, the same as the single command would.
+func prepareBulkRow(reg *registry, row *bulkRow, op bulkOp) error {
+	chainID := layerZeroID
+	if row.Chain != "" {
+		chain := reg.findChain(row.Chain)
+		if chain == nil {
+			return fmt.Errorf("unknown chain %q", row.Chain)
+		}
+		chainID = chain.ID
+	}
+	row.Chain = strconv.FormatUint(uint64(chainID), 10)
+
+	assetName := row.Asset
+	if assetName == "" {
+		assetName = defaultAssetID
+	}
+	if assetName == "" {
+		return fmt.Errorf("no asset, set the asset column or --defaultAssetID")
+	}
+	asset, err := reg.resolveAsset(assetName, chainID)
+	if err != nil {
+		return err
+	}
+	row.Asset = asset.Address
+	if err := validateAssetAddress(asset.Address); err != nil {
+		return fmt.Errorf("asset: %v", err)
+	}
+
+	if err := validateHexAddress(row.Staker); err != nil {
+		return fmt.Errorf("staker: %v", err)
+	}
+	if op.operator {
+		if row.Operator == "" {
+			return fmt.Errorf("operator is required")
+		}
+		if err := validateOperator(row.Operator); err != nil {
+			return fmt.Errorf("operator: %v", err)
+		}
+	}
+	amount, err := parseAmount(row.Amount, asset)
+	if err != nil {
+		return err
+	}
+
+	row.to = common.HexToAddress(op.to())
+	row.abi, row.data, err = op.pack(chainID, asset.Address, row.Staker, row.Operator, amount)
+	return err
+}
+
+func bulk_(rpcUrl, csvFile, resultsFile string, concurrency int, op bulkOp) error {
+	if concurrency < 1 {
+		return withCode(errCodeInvalidArguments, fmt.Errorf("--concurrency must be at least 1"))
+	}
+	if resultsFile == "" {
+		resultsFile = strings.TrimSuffix(csvFile, ".csv") + ".results.csv"
+	}
+	rows, err := readBulkCSV(csvFile)
+	if err != nil {
+		return withCode(errCodeInvalidArguments, err)
+	}
+	previous, err := loadBulkResults(resultsFile)
+	if err != nil {
+		return err
+	}
+	reg, err := loadRegistry()
+	if err != nil {
+		return err
+	}
+
+	// rows are checked and packed before anything is signed
+	for _, row := range rows {
+		if err := prepareBulkRow(reg, row, op); err != nil {
+			row.Status, row.Error = rowError, err.Error()
+			fmt.Fprintf(textOut, "Row %d: %v\n", row.Row, err)
+		}
+	}
+	// keys use the resolved asset and chain, as written to the results
+	numberOccurrences(rows)
+	var todo []*bulkRow
+	for _, row := range rows {
+		if row.Status == rowError {
+			continue
+		}
+		if done, ok := previous[row.key()]; ok && (done.Status == rowConfirmed || done.Status == rowSent) {
+			row.TxHash, row.Status = done.TxHash, done.Status
+		}
+		if row.Status == rowConfirmed {
+			fmt.Fprintf(textOut, "Row %d: already confirmed in %s\n", row.Row, row.TxHash)
+			continue
+		}
+		todo = append(todo, row)
+	}
+
+	if len(todo) > 0 {
+		if err := sendBulkRows(rpcUrl, resultsFile, rows, todo, concurrency, op); err != nil {
+			return err
+		}
+	}
+	if !dryRun {
+		if err := writeBulkResults(resultsFile, rows); err != nil {
+			return err
+		}
+		fmt.Fprintln(textOut, "Results written to", resultsFile)
+	}
+
+	setResult(rows)
+	counts := make(map[string]int)
+	for _, row := range rows {
+		counts[row.Status]++
+	}
+	if dryRun {
+		fmt.Fprintf(textOut, "%d rows: %d simulated, %d already confirmed, %d errors\n", len(rows), counts[rowSimulated], counts[rowConfirmed], counts[rowError])
+	} else {
+		fmt.Fprintf(textOut, "%d rows: %d confirmed, %d sent, %d failed, %d errors\n", len(rows), counts[rowConfirmed], counts[rowSent], counts[rowFailed], counts[rowError])
+	}
+	if failed := counts[rowFailed] + counts[rowError]; failed > 0 {
+		return withCode(errCodeTxFailed, fmt.Errorf("%d of %d rows failed, see %s", failed, len(rows), resultsFile))
+	}
+	if pending := counts[rowSent]; pending > 0 && !noWait {
+		return withCode(errCodeTimeout, fmt.Errorf("%d of %d rows are not mined yet, run again to wait for them", pending, len(rows)))
+	}
+	return nil
+}
+
+// sendBulkRows sends the rows in order with nonces from the account's nonce
+// manager, keeping at most concurrency transactions unconfirmed. A row left as
+// sent by an earlier run is waited for instead of sent again, unless it was dropped.
+func sendBulkRows(rpcUrl, resultsFile string, rows, todo []*bulkRow, concurrency int, op bulkOp) error {
+	// a dry run only simulates, so it needs no key: the calls are made from
+	// --from when that is an address, else from the zero address
+	var signer Signer
+	var sender common.Address
+	if dryRun {
+		if common.IsHexAddress(from) {
+			sender = common.HexToAddress(from)
+		}
+	} else {
+		var err error
+		signer, err = loadSigner()
+		if err != nil {
+			return withCode(errCodeSigner, err)
+		}
+		sender = signer.Address()
+		report.From = &sender
+	}
+
+	_, ethClient, err := connectToEthereum(rpcUrl)
+	if err != nil {
+		return err
+	}
+	chainID, err := ethClient.ChainID(context.Background())
+	if err != nil {
+		return err
+	}
+
+	var mu sync.Mutex
+	save := func(row *bulkRow, status, txHash, message string) {
+		mu.Lock()
+		defer mu.Unlock()
+		row.Status, row.TxHash, row.Error = status, txHash, message
+		if message != "" {
+			fmt.Fprintf(textOut, "Row %d: %s %s %s\n", row.Row, status, txHash, message)
+		} else {
+			fmt.Fprintf(textOut, "Row %d: %s %s\n", row.Row, status, txHash)
+		}
+		if dryRun {
+			return
+		}
+		if err := writeBulkResults(resultsFile, rows); err != nil {
+			fmt.Fprintln(textOut, "Could not write results:", err)
+		}
+	}
+
+	slots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	wait := func(row *bulkRow, txID string) {
+		defer wg.Done()
+		defer func() { <-slots }()
+		receipt, err := waitWithFeeBumps(ethClient, chainID, signer, txID)
+		if receipt != nil {
+			// a fee bump may have replaced txID
+			txID = receipt.TxHash.Hex()
+		}
+		switch {
+		case err == nil:
+			save(row, rowConfirmed, txID, "")
+		case errorCode(err) == errCodeTxFailed:
+			save(row, rowFailed, txID, err.Error())
+		default:
+			// still unknown, a re-run picks the hash up again
+			save(row, rowSent, txID, err.Error())
+		}
+	}
+
+	for _, row := range todo {
+		slots <- struct{}{}
+		if row.Status == rowSent && row.TxHash != "" && !dryRun {
+			state, err := getTxState(context.Background(), ethClient, common.HexToHash(row.TxHash))
+			// resending a tx that could still be pending risks sending the row twice,
+			// so an unknown state is waited for like a pending one
+			if err != nil || state.Status != txStatusDropped && state.Status != txStatusFailed {
+				fmt.Fprintf(textOut, "Row %d: waiting for %s sent earlier\n", row.Row, row.TxHash)
+				wg.Add(1)
+				go wait(row, row.TxHash)
+				continue
+			}
+		}
+
+		if _, err := preflight(context.Background(), ethClient, sender, row.to, row.abi, row.data); err != nil {
+			save(row, rowError, "", err.Error())
+			<-slots
+			continue
+		}
+		if dryRun {
+			save(row, rowSimulated, "", "")
+			<-slots
+			continue
+		}
+		txID, err := sendTransaction(ethClient, chainID, signer, row.to, row.data)
+		if err != nil {
+			save(row, rowError, "", err.Error())
+			<-slots
+			continue
+		}
+		save(row, rowSent, txID, "")
+		if noWait {
+			<-slots
+			continue
+		}
+		wg.Add(1)
+		go wait(row, txID)
+	}
+	wg.Wait()
+	return nil
+}
@@ -0,0 +1,409 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const bulkTestStaker = "0x3e108c058e8066da635321dc3018294ca82ddedf"
+
+// bulkNode is an in-process stand-in for the eth_ JSON-RPC methods bulk uses.
+// Calls succeed and transactions are mined as soon as they are sent; lookups
+// of hashes in broken fail, as on a node that cannot tell their state.
+type bulkNode struct {
+	mu       sync.Mutex
+	txs      map[common.Hash]*types.Transaction
+	receipts map[common.Hash]*types.Receipt
+	broken   map[common.Hash]bool
+	sent     map[string]int
+}
+
+func newBulkNode(t *testing.T) (*bulkNode, string) {
+	node := &bulkNode{
+		txs:      make(map[common.Hash]*types.Transaction),
+		receipts: make(map[common.Hash]*types.Receipt),
+		broken:   make(map[common.Hash]bool),
+		sent:     make(map[string]int),
+	}
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", node); err != nil {
+		t.Fatal(err)
+	}
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+	return node, httpServer.URL
+}
+
+func (n *bulkNode) ChainId() *hexutil.Big {
+	return (*hexutil.Big)(big.NewInt(232))
+}
+
+func (n *bulkNode) BlockNumber() hexutil.Uint64 {
+	return 1
+}
+
+func (n *bulkNode) GetTransactionCount(address common.Address, block string) hexutil.Uint64 {
+	return 0
+}
+
+func (n *bulkNode) EstimateGas(args map[string]interface{}, block *string) hexutil.Uint64 {
+	return 100000
+}
+
+// Call returns success=true followed by a zero word, which decodes as the
+// outputs of every bulk op.
+func (n *bulkNode) Call(args map[string]interface{}, block string) hexutil.Bytes {
+	out := make([]byte, 64)
+	out[31] = 1
+	return out
+}
+
+func (n *bulkNode) SendRawTransaction(raw hexutil.Bytes) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(raw); err != nil {
+		return common.Hash{}, err
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.sent[hexutil.Encode(tx.Data())]++
+	n.mine(tx)
+	return tx.Hash(), nil
+}
+
+// mine records a successful receipt for tx, the caller holding mu.
+func (n *bulkNode) mine(tx *types.Transaction) {
+	n.txs[tx.Hash()] = tx
+	n.receipts[tx.Hash()] = &types.Receipt{
+		Status:      types.ReceiptStatusSuccessful,
+		GasUsed:     60000,
+		Logs:        []*types.Log{},
+		TxHash:      tx.Hash(),
+		BlockNumber: big.NewInt(1),
+		BlockHash:   common.HexToHash("0x01"),
+	}
+}
+
+func (n *bulkNode) GetTransactionReceipt(hash common.Hash) (*types.Receipt, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.broken[hash] {
+		return nil, fmt.Errorf("receipt index unavailable")
+	}
+	return n.receipts[hash], nil
+}
+
+func (n *bulkNode) GetTransactionByHash(hash common.Hash) (*types.Transaction, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.broken[hash] {
+		return nil, fmt.Errorf("transaction index unavailable")
+	}
+	return n.txs[hash], nil
+}
+
+// setBulkGlobals points the flag variables bulk reads at a test mnemonic,
+// a registry in dir and a legacy fee.
+func setBulkGlobals(t *testing.T, dir string) {
+	mnemonicPath := filepath.Join(dir, "mnemonic.txt")
+	if err := os.WriteFile(mnemonicPath, []byte(benchTestMnemonic), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	setGlobal(t, &mnemonicFile, mnemonicPath)
+	setGlobal(t, &hdPath, ethHDPath)
+	setGlobal(t, &accountIndex, 0)
+	setGlobal(t, &defaultAssetID, benchTestAsset)
+	setGlobal(t, &layerZeroID, 101)
+	setGlobal(t, &registryFile, filepath.Join(dir, "registry.json"))
+	setGlobal(t, &txType, txTypeLegacy)
+	setGlobal(t, &gasPrice, "1000000000")
+	setGlobal(t, &gasMultiplier, 1)
+	setGlobal(t, &waitTimeout, 10*time.Second)
+	setGlobal(t, &dryRun, false)
+	setGlobal(t, &noWait, false)
+	setGlobal(t, &report, &commandReport{})
+}
+
+func writeBulkCSV(t *testing.T, path string, amounts []string) {
+	lines := []string{"staker,amount"}
+	for _, amount := range amounts {
+		lines = append(lines, bulkTestStaker+","+amount)
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBulkRowKeys(t *testing.T) {
+	upper := func(address string) string { return "0x" + strings.ToUpper(address[2:]) }
+	rows := []*bulkRow{
+		{Staker: bulkTestStaker, Amount: "1", Asset: benchTestAsset, Chain: "101"},
+		{Staker: upper(bulkTestStaker), Amount: "1", Asset: upper(benchTestAsset), Chain: "101"},
+		{Staker: bulkTestStaker, Amount: "1", Asset: benchTestAsset, Chain: "101"},
+		{Staker: bulkTestStaker, Amount: "2", Asset: benchTestAsset, Chain: "101"},
+		{Staker: bulkTestStaker, Amount: "1", Asset: benchTestAsset, Chain: "40161"},
+	}
+	numberOccurrences(rows)
+
+	// the first three rows only differ in case, so they are told apart by occurrence
+	wantOccurrences := []int{0, 1, 2, 0, 0}
+	keys := make(map[string]int)
+	for i, row := range rows {
+		if row.occurrence != wantOccurrences[i] {
+			t.Errorf("row %d: occurrence %d, want %d", i, row.occurrence, wantOccurrences[i])
+		}
+		if other, ok := keys[row.key()]; ok {
+			t.Errorf("rows %d and %d share the key %s", other, i, row.key())
+		}
+		keys[row.key()] = i
+	}
+	if rows[0].content() != rows[1].content() {
+		t.Errorf("content differs only by case: %q, %q", rows[0].content(), rows[1].content())
+	}
+}
+
+func TestLoadBulkResults(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "results.csv")
+
+	previous, err := loadBulkResults(path)
+	if err != nil || len(previous) != 0 {
+		t.Fatalf("loadBulkResults of a missing file = %v, %v, want no rows", previous, err)
+	}
+
+	rows := []*bulkRow{
+		{Row: 1, Staker: bulkTestStaker, Amount: "1", Asset: benchTestAsset, Chain: "101", TxHash: "0x01", Status: rowConfirmed},
+		{Row: 2, Staker: bulkTestStaker, Amount: "1", Asset: benchTestAsset, Chain: "101", TxHash: "0x02", Status: rowSent},
+		{Row: 3, Staker: bulkTestStaker, Amount: "2", Operator: "exo1abc", Asset: benchTestAsset, Chain: "101", Status: rowError, Error: "execution reverted, \"quoted\""},
+	}
+	if err := writeBulkResults(path, rows); err != nil {
+		t.Fatalf("writeBulkResults: %v", err)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("temporary results file left behind: %v", err)
+	}
+	previous, err = loadBulkResults(path)
+	if err != nil {
+		t.Fatalf("loadBulkResults: %v", err)
+	}
+	numberOccurrences(rows)
+	if len(previous) != len(rows) {
+		t.Fatalf("got %d rows, want %d", len(previous), len(rows))
+	}
+	for _, want := range rows {
+		got := previous[want.key()]
+		if got == nil {
+			t.Errorf("row %d missing under %s", want.Row, want.key())
+			continue
+		}
+		if got.Row != want.Row || got.TxHash != want.TxHash || got.Status != want.Status || got.Error != want.Error || got.Operator != want.Operator {
+			t.Errorf("row %d = %+v, want %+v", want.Row, got, want)
+		}
+	}
+
+	if err := os.WriteFile(path, []byte(strings.Join(bulkResultColumns, ",")+"\nx,a,1,,b,101,,sent,\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadBulkResults(path); err == nil || !strings.Contains(err.Error(), "invalid row") {
+		t.Errorf("loadBulkResults error = %v, want an invalid row error", err)
+	}
+}
+
+func TestPrepareBulkRowRejectsInvalidRows(t *testing.T) {
+	setGlobal(t, &layerZeroID, 101)
+	setGlobal(t, &defaultAssetID, benchTestAsset)
+	setGlobal(t, &bech32HRP, "exo")
+	operator, err := addressToBech32("exo", common.HexToAddress(benchTestOperator))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		op   string
+		row  bulkRow
+		want string
+	}{
+		{"short staker", "deposit", bulkRow{Staker: "0x1234", Amount: "1"}, "staker"},
+		{"negative amount", "deposit", bulkRow{Staker: bulkTestStaker, Amount: "-1"}, "amount"},
+		{"zero amount", "deposit", bulkRow{Staker: bulkTestStaker, Amount: "0"}, "amount"},
+		{"short asset", "deposit", bulkRow{Staker: bulkTestStaker, Amount: "1", Asset: "0x1234"}, "asset"},
+		{"unknown symbol", "deposit", bulkRow{Staker: bulkTestStaker, Amount: "1", Asset: "NOPE"}, "no asset"},
+		{"unknown chain", "deposit", bulkRow{Staker: bulkTestStaker, Amount: "1", Chain: "nowhere"}, "unknown chain"},
+		{"missing operator", "delegate", bulkRow{Staker: bulkTestStaker, Amount: "1"}, "operator is required"},
+		{"invalid operator", "delegate", bulkRow{Staker: bulkTestStaker, Amount: "1", Operator: "exo1nope"}, "operator"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			row := tt.row
+			err := prepareBulkRow(&registry{}, &row, bulkOps[tt.op])
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("prepareBulkRow error = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+
+	t.Run("no asset", func(t *testing.T) {
+		setGlobal(t, &defaultAssetID, "")
+		row := bulkRow{Staker: bulkTestStaker, Amount: "1"}
+		if err := prepareBulkRow(&registry{}, &row, bulkOps["deposit"]); err == nil || !strings.Contains(err.Error(), "no asset") {
+			t.Fatalf("prepareBulkRow error = %v, want a missing asset error", err)
+		}
+	})
+
+	row := bulkRow{Staker: bulkTestStaker, Amount: "1", Operator: operator, Chain: "sepolia"}
+	if err := prepareBulkRow(&registry{}, &row, bulkOps["delegate"]); err != nil {
+		t.Fatalf("prepareBulkRow: %v", err)
+	}
+	if row.Chain != "40161" || !strings.EqualFold(row.Asset, benchTestAsset) || len(row.data) < 4 {
+		t.Errorf("prepared row = %+v, want chain 40161, asset %s and calldata", row, benchTestAsset)
+	}
+}
+
+func TestBulkResumeDoesNotResend(t *testing.T) {
+	dir := t.TempDir()
+	setBulkGlobals(t, dir)
+	node, url := newBulkNode(t)
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// previous is the status of the row in the results of an earlier run, and
+	// state what the node knows of the hash recorded there
+	tests := []struct {
+		name     string
+		amount   string
+		previous string
+		state    string
+		resent   bool
+		status   string
+	}{
+		{"new row", "1", "", "", true, rowConfirmed},
+		{"confirmed", "2", rowConfirmed, "none", false, rowConfirmed},
+		{"sent and mined", "3", rowSent, "mined", false, rowConfirmed},
+		{"sent of unknown state", "4", rowSent, "unknown", false, rowSent},
+		{"sent and dropped", "5", rowSent, "none", true, rowConfirmed},
+		{"failed", "6", rowFailed, "none", true, rowConfirmed},
+		{"error", "7", rowError, "", true, rowConfirmed},
+		{"first of identical rows", "8", rowConfirmed, "none", false, rowConfirmed},
+		{"second of identical rows", "8", "", "", true, rowConfirmed},
+	}
+
+	var amounts []string
+	var previous []*bulkRow
+	hashes := make(map[int]string)
+	for i, tt := range tests {
+		amounts = append(amounts, tt.amount)
+		if tt.previous == "" {
+			continue
+		}
+		hash := common.BigToHash(big.NewInt(int64(i + 1)))
+		switch tt.state {
+		case "mined":
+			tx, err := types.SignNewTx(key, types.LatestSignerForChainID(big.NewInt(232)), &types.LegacyTx{Nonce: uint64(i), Gas: 21000, GasPrice: big.NewInt(1)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			node.mine(tx)
+			hash = tx.Hash()
+		case "unknown":
+			node.broken[hash] = true
+		}
+		hashes[i] = hash.Hex()
+		previous = append(previous, &bulkRow{Row: i + 1, Staker: bulkTestStaker, Amount: tt.amount, Asset: benchTestAsset, Chain: "101", TxHash: hash.Hex(), Status: tt.previous})
+	}
+	// rows are matched by content, not by line, so the order of the results does not matter
+	for i, j := 0, len(previous)-1; i < j; i, j = i+1, j-1 {
+		previous[i], previous[j] = previous[j], previous[i]
+	}
+	csvPath := filepath.Join(dir, "rows.csv")
+	resultsPath := filepath.Join(dir, "rows.results.csv")
+	writeBulkCSV(t, csvPath, amounts)
+	if err := writeBulkResults(resultsPath, previous); err != nil {
+		t.Fatal(err)
+	}
+
+	err = bulk_(url, csvPath, "", 2, bulkOps["deposit"])
+	if errorCode(err) != errCodeTimeout {
+		t.Fatalf("bulk_ error = %v, want %s for the row of unknown state", err, errCodeTimeout)
+	}
+	rows, ok := report.Result.([]*bulkRow)
+	if !ok || len(rows) != len(tests) {
+		t.Fatalf("unexpected result %T with %d rows", report.Result, len(rows))
+	}
+
+	wantSent := make(map[string]int)
+	for i, tt := range tests {
+		row := rows[i]
+		if tt.resent {
+			wantSent[hexutil.Encode(row.data)]++
+		}
+		if row.Status != tt.status {
+			t.Errorf("%s: status %s, want %s (%s)", tt.name, row.Status, tt.status, row.Error)
+		}
+		if !tt.resent && row.TxHash != hashes[i] {
+			t.Errorf("%s: hash %s, want %s from the earlier run", tt.name, row.TxHash, hashes[i])
+		}
+	}
+	for data, count := range node.sent {
+		if count != wantSent[data] {
+			t.Errorf("calldata %s sent %d times, want %d", data[:10], count, wantSent[data])
+		}
+	}
+	for data, count := range wantSent {
+		if node.sent[data] != count {
+			t.Errorf("calldata %s sent %d times, want %d", data[:10], node.sent[data], count)
+		}
+	}
+
+	saved, err := loadBulkResults(resultsPath)
+	if err != nil {
+		t.Fatalf("loadBulkResults: %v", err)
+	}
+	for _, row := range rows {
+		if got := saved[row.key()]; got == nil || got.Status != row.Status || got.TxHash != row.TxHash {
+			t.Errorf("row %d saved as %+v, want status %s and hash %s", row.Row, got, row.Status, row.TxHash)
+		}
+	}
+}
+
+func TestBulkDryRunNeedsNoKey(t *testing.T) {
+	dir := t.TempDir()
+	setBulkGlobals(t, dir)
+	setGlobal(t, &mnemonicFile, "")
+	setGlobal(t, &privateKey, "")
+	setGlobal(t, &from, "")
+	setGlobal(t, &dryRun, true)
+	node, url := newBulkNode(t)
+
+	csvPath := filepath.Join(dir, "rows.csv")
+	writeBulkCSV(t, csvPath, []string{"1", "2"})
+	if err := bulk_(url, csvPath, "", 1, bulkOps["deposit"]); err != nil {
+		t.Fatalf("bulk_: %v", err)
+	}
+	rows, _ := report.Result.([]*bulkRow)
+	for _, row := range rows {
+		if row.Status != rowSimulated {
+			t.Errorf("row %d: status %s, want %s (%s)", row.Row, row.Status, rowSimulated, row.Error)
+		}
+	}
+	if len(node.sent) != 0 {
+		t.Errorf("a dry run sent %d transactions", len(node.sent))
+	}
+	if _, err := os.Stat(filepath.Join(dir, "rows.results.csv")); !os.IsNotExist(err) {
+		t.Errorf("a dry run wrote results: %v", err)
+	}
+}
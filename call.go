@@ -0,0 +1,367 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/spf13/cobra"
+)
+
+// precompileContract is a precompile the call command can reach by name.
+type precompileContract struct {
+	abi     string
+	address func() string
+}
+
+var precompileContracts = map[string]precompileContract{
+	"assets":     {DepositABI, func() string { return depositPrecompileAddress }},
+	"delegation": {DelegateABI, func() string { return delegatePrecompileAddress }},
+	"reward":     {rewardABI, func() string { return rewardPrecompileAddress }},
+}
+
+var callCmd = &cobra.Command{
+	Use:   "call <assets|delegation|reward> [method]",
+	Short: "Call any precompile method with JSON arguments, or list the methods when none is given",
+	Long: `Call any method of the embedded precompile ABIs. --args is a JSON array of the
+arguments in order, or an object keyed by argument name. Tuples are objects keyed
+by field name or arrays; integers are numbers or decimal / 0x strings; bytes are
+0x hex, or the raw string otherwise (e.g. a bech32 operator). view and pure methods
+are called with eth_call, the others are sent as a transaction.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 1 {
+			err := listMethods_(args[0])
+			if err != nil {
+				return failf("Failed to list methods: %v", err)
+			}
+			return nil
+		}
+		rpcUrl, _ := cmd.Flags().GetString("rpcUrl")
+		argsJSON, _ := cmd.Flags().GetString("args")
+		err := call_(rpcUrl, args[0], args[1], argsJSON)
+		if err != nil {
+			return failf("Failed to call %s: %v", args[1], err)
+		}
+		return nil
+	},
+}
+
+func registerCallCommands() {
+	rootCmd.AddCommand(callCmd)
+
+	callCmd.Flags().String("rpcUrl", "http://localhost:8545", "Exocore RPC URL")
+	callCmd.Flags().String("args", "[]", "Method arguments as a JSON array, or an object keyed by argument name")
+}
+
+func loadPrecompile(name string) (common.Address, abi.ABI, error) {
+	contract, ok := precompileContracts[name]
+	if !ok {
+		return common.Address{}, abi.ABI{}, withCode(errCodeInvalidArguments, fmt.Errorf("unknown precompile %q, expected assets, delegation or reward", name))
+	}
+	contractAbi, err := abi.JSON(strings.NewReader(contract.abi))
+	if err != nil {
+		return common.Address{}, abi.ABI{}, err
+	}
+	return common.HexToAddress(contract.address()), contractAbi, nil
+}
+
+// isReadOnly reports whether method is called rather than sent.
+func isReadOnly(method abi.Method) bool {
+	return method.StateMutability == "view" || method.StateMutability == "pure" || method.Constant
+}
+
+func listMethods_(name string) error {
+	to, contractAbi, err := loadPrecompile(name)
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(contractAbi.Methods))
+	for methodName := range contractAbi.Methods {
+		names = append(names, methodName)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintf(textOut, "%s precompile %s\n", name, to.Hex())
+	methods := make([]map[string]string, 0, len(names))
+	for _, methodName := range names {
+		method := contractAbi.Methods[methodName]
+		fmt.Fprintf(textOut, "  %-60s %s\n", method.Sig, method.StateMutability)
+		methods = append(methods, map[string]string{"name": method.Name, "signature": method.Sig, "stateMutability": method.StateMutability})
+	}
+	setResult(map[string]interface{}{"precompile": to.Hex(), "methods": methods})
+	return nil
+}
+
+func call_(rpcUrl, contractName, methodName, argsJSON string) error {
+	to, contractAbi, err := loadPrecompile(contractName)
+	if err != nil {
+		return err
+	}
+	method, ok := contractAbi.Methods[methodName]
+	if !ok {
+		return withCode(errCodeInvalidArguments, fmt.Errorf("%s has no method %q, list them with call %s", contractName, methodName, contractName))
+	}
+	values, err := parseMethodArgs(method, argsJSON)
+	if err != nil {
+		return withCode(errCodeInvalidArguments, err)
+	}
+	data, err := contractAbi.Pack(method.Name, values...)
+	if err != nil {
+		return withCode(errCodeInvalidArguments, err)
+	}
+
+	if !isReadOnly(method) {
+		return executeTx(rpcUrl, method.Name, to, contractAbi, data)
+	}
+
+	recordCall(method.Name, to, contractAbi, data)
+	_, ethClient, err := connectToEthereum(rpcUrl)
+	if err != nil {
+		return err
+	}
+	result, err := ethClient.CallContract(context.Background(), ethereum.CallMsg{To: &to, Data: data}, nil)
+	if err != nil {
+		if reason, ok := revertReason(err); ok {
+			return fmt.Errorf("%s reverted: %s", method.Name, reason)
+		}
+		return err
+	}
+	outputs, err := method.Outputs.Unpack(result)
+	if err != nil {
+		return err
+	}
+	decoded := namedValues(method.Outputs, outputs)
+	report.Outputs = decoded
+	fmt.Fprintln(textOut, "Method:", method.Sig)
+	for _, output := range decoded {
+		fmt.Fprintf(textOut, "  %s (%s): %v\n", output.Name, output.Type, output.Value)
+	}
+	return nil
+}
+
+// parseMethodArgs converts --args, a JSON array or an object keyed by input
+// name, into the values abi.Pack expects for method's inputs.
+func parseMethodArgs(method abi.Method, argsJSON string) ([]interface{}, error) {
+	var raw []json.RawMessage
+	trimmed := strings.TrimSpace(argsJSON)
+	if strings.HasPrefix(trimmed, "{") {
+		var named map[string]json.RawMessage
+		if err := json.Unmarshal([]byte(trimmed), &named); err != nil {
+			return nil, fmt.Errorf("--args: %v", err)
+		}
+		for _, input := range method.Inputs {
+			value, ok := named[input.Name]
+			if !ok {
+				return nil, fmt.Errorf("--args: missing %s (%s)", input.Name, input.Type)
+			}
+			raw = append(raw, value)
+			delete(named, input.Name)
+		}
+		for name := range named {
+			return nil, fmt.Errorf("--args: %s has no argument %q", method.Name, name)
+		}
+	} else if err := json.Unmarshal([]byte(trimmed), &raw); err != nil {
+		return nil, fmt.Errorf("--args must be a JSON array or object: %v", err)
+	}
+	if len(raw) != len(method.Inputs) {
+		return nil, fmt.Errorf("%s takes %d arguments, got %d", method.Sig, len(method.Inputs), len(raw))
+	}
+
+	values := make([]interface{}, len(raw))
+	for i, input := range method.Inputs {
+		value, err := abiValue(input.Type, raw[i])
+		if err != nil {
+			return nil, fmt.Errorf("%s (%s): %v", input.Name, input.Type, err)
+		}
+		values[i] = value.Interface()
+	}
+	return values, nil
+}
+
+// abiValue converts raw into a value of t's Go type, as abi.Pack expects it.
+// Tuples become the struct type go-ethereum generates for them.
+func abiValue(t abi.Type, raw json.RawMessage) (reflect.Value, error) {
+	goType := t.GetType()
+	switch t.T {
+	case abi.IntTy, abi.UintTy:
+		n, err := jsonInteger(raw)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if !fitsInt(n, t) {
+			return reflect.Value{}, fmt.Errorf("%s does not fit in %s", n, t)
+		}
+		if goType.Kind() == reflect.Ptr {
+			return reflect.ValueOf(n), nil
+		}
+		value := reflect.New(goType).Elem()
+		if t.T == abi.UintTy {
+			value.SetUint(n.Uint64())
+		} else {
+			value.SetInt(n.Int64())
+		}
+		return value, nil
+
+	case abi.BoolTy:
+		var b bool
+		if err := json.Unmarshal(raw, &b); err != nil {
+			var s string
+			if json.Unmarshal(raw, &s) != nil || (s != "true" && s != "false") {
+				return reflect.Value{}, fmt.Errorf("expected true or false, got %s", raw)
+			}
+			b = s == "true"
+		}
+		return reflect.ValueOf(b), nil
+
+	case abi.StringTy:
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return reflect.Value{}, fmt.Errorf("expected a string, got %s", raw)
+		}
+		return reflect.ValueOf(s), nil
+
+	case abi.AddressTy:
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return reflect.Value{}, fmt.Errorf("expected an address string, got %s", raw)
+		}
+		if err := validateHexAddress(s); err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(common.HexToAddress(s)), nil
+
+	case abi.BytesTy:
+		b, err := jsonBytes(raw)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(b), nil
+
+	case abi.FixedBytesTy:
+		b, err := jsonBytes(raw)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if len(b) != t.Size {
+			return reflect.Value{}, fmt.Errorf("expected %d bytes, got %d", t.Size, len(b))
+		}
+		value := reflect.New(goType).Elem()
+		reflect.Copy(value, reflect.ValueOf(b))
+		return value, nil
+
+	case abi.SliceTy, abi.ArrayTy:
+		var items []json.RawMessage
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return reflect.Value{}, fmt.Errorf("expected a JSON array, got %s", raw)
+		}
+		var value reflect.Value
+		if t.T == abi.ArrayTy {
+			if len(items) != t.Size {
+				return reflect.Value{}, fmt.Errorf("expected %d items, got %d", t.Size, len(items))
+			}
+			value = reflect.New(goType).Elem()
+		} else {
+			value = reflect.MakeSlice(goType, len(items), len(items))
+		}
+		for i, item := range items {
+			elem, err := abiValue(*t.Elem, item)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("[%d]: %v", i, err)
+			}
+			value.Index(i).Set(elem)
+		}
+		return value, nil
+
+	case abi.TupleTy:
+		fields, err := tupleFields(t, raw)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		value := reflect.New(goType).Elem()
+		for i, elem := range t.TupleElems {
+			field, err := abiValue(*elem, fields[i])
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("%s: %v", t.TupleRawNames[i], err)
+			}
+			value.Field(i).Set(field)
+		}
+		return value, nil
+	}
+	return reflect.Value{}, fmt.Errorf("unsupported type %s", t)
+}
+
+// tupleFields splits a tuple given as an object keyed by field name, or as an
+// array in field order.
+func tupleFields(t abi.Type, raw json.RawMessage) ([]json.RawMessage, error) {
+	var fields []json.RawMessage
+	if bytes.HasPrefix(bytes.TrimSpace(raw), []byte("[")) {
+		if err := json.Unmarshal(raw, &fields); err != nil {
+			return nil, err
+		}
+		if len(fields) != len(t.TupleElems) {
+			return nil, fmt.Errorf("expected %d fields, got %d", len(t.TupleElems), len(fields))
+		}
+		return fields, nil
+	}
+	var named map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &named); err != nil {
+		return nil, fmt.Errorf("expected a JSON object or array, got %s", raw)
+	}
+	for _, name := range t.TupleRawNames {
+		value, ok := named[name]
+		if !ok {
+			return nil, fmt.Errorf("missing field %s", name)
+		}
+		fields = append(fields, value)
+		delete(named, name)
+	}
+	for name := range named {
+		return nil, fmt.Errorf("unknown field %s, expected %s", name, strings.Join(t.TupleRawNames, ", "))
+	}
+	return fields, nil
+}
+
+// fitsInt reports whether n is in the range of the int or uint type t.
+func fitsInt(n *big.Int, t abi.Type) bool {
+	if t.T == abi.UintTy {
+		return n.Sign() >= 0 && n.BitLen() <= t.Size
+	}
+	limit := new(big.Int).Lsh(big.NewInt(1), uint(t.Size-1))
+	return n.Cmp(new(big.Int).Neg(limit)) >= 0 && n.Cmp(limit) < 0
+}
+
+// jsonInteger reads a JSON number, or a decimal or 0x hex string.
+func jsonInteger(raw json.RawMessage) (*big.Int, error) {
+	text := strings.TrimSpace(string(raw))
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		text = strings.TrimSpace(s)
+	}
+	n, ok := new(big.Int).SetString(text, 0)
+	if !ok {
+		return nil, fmt.Errorf("expected an integer, got %s", raw)
+	}
+	return n, nil
+}
+
+// jsonBytes reads a 0x hex string, or any other string as its raw bytes.
+func jsonBytes(raw json.RawMessage) ([]byte, error) {
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return nil, fmt.Errorf("expected a string, got %s", raw)
+	}
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		return hexutil.Decode("0x" + s[2:])
+	}
+	return []byte(s), nil
+}
@@ -0,0 +1,359 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	configFile  string
+	profileName string
+)
+
+// config is ~/.assetcli/config.yaml: named profiles of flag values, and the
+// profile used when neither --profile nor ASSETCLI_PROFILE is set.
+type config struct {
+	Profile  string                       `yaml:"profile,omitempty"`
+	Profiles map[string]map[string]string `yaml:"profiles"`
+}
+
+// profileSetting is a flag a profile can set, with the environment variable
+// that overrides the profile.
+type profileSetting struct {
+	key   string
+	env   string
+	check func(value string) error
+}
+
+// profileSettings are applied in order flag > env > profile > default.
+var profileSettings = []profileSetting{
+	{"rpcUrl", "ASSETCLI_RPC_URL", validateURL},
+	{"apiUrl", "ASSETCLI_API_URL", validateURL},
+	{"layerZeroID", "ASSETCLI_LAYERZERO_ID", validateUint32},
+	{"defaultAssetID", "ASSETCLI_DEFAULT_ASSET_ID", validateAssetAddress},
+	{"hrp", "ASSETCLI_HRP", nil},
+	{"signer", "ASSETCLI_SIGNER", nil},
+	{"from", "ASSETCLI_FROM", nil},
+	{"deposit-precompile", "ASSETCLI_DEPOSIT_PRECOMPILE", validateHexAddress},
+	{"delegate-precompile", "ASSETCLI_DELEGATE_PRECOMPILE", validateHexAddress},
+	{"reward-precompile", "ASSETCLI_REWARD_PRECOMPILE", validateHexAddress},
+}
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage network profiles in the config file",
+}
+
+var configInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Write a config file with a local profile",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		err := configInit_()
+		if err != nil {
+			return failf("Failed to init config: %v", err)
+		}
+		return nil
+	},
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the settings of the selected profile and where they come from",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		err := configShow_()
+		if err != nil {
+			return failf("Failed to show config: %v", err)
+		}
+		return nil
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Set a value in the selected profile, or the default profile with key profile",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		err := configSet_(args[0], args[1])
+		if err != nil {
+			return failf("Failed to set config: %v", err)
+		}
+		return nil
+	},
+}
+
+func registerConfigCommands() {
+	rootCmd.PersistentFlags().StringVar(&configFile, "config", defaultConfigPath(), "Config file with network profiles")
+	rootCmd.PersistentFlags().StringVar(&profileName, "profile", "", "Profile of the config file to use, defaults to ASSETCLI_PROFILE or the file's profile")
+	rootCmd.PersistentFlags().StringVar(&depositPrecompileAddress, "deposit-precompile", depositPrecompileAddress, "Address of the assets precompile")
+	rootCmd.PersistentFlags().StringVar(&delegatePrecompileAddress, "delegate-precompile", delegatePrecompileAddress, "Address of the delegation precompile")
+	rootCmd.PersistentFlags().StringVar(&rewardPrecompileAddress, "reward-precompile", rewardPrecompileAddress, "Address of the reward precompile")
+
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configInitCmd)
+	configCmd.AddCommand(configShowCmd)
+	configCmd.AddCommand(configSetCmd)
+}
+
+func defaultConfigPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".assetcli", "config.yaml")
+	}
+	return filepath.Join(home, ".assetcli", "config.yaml")
+}
+
+// loadConfig reads --config, an absent file being an empty config.
+func loadConfig() (*config, error) {
+	cfg := &config{}
+	raw, err := os.ReadFile(configFile)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(raw, cfg); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %v", configFile, err)
+	}
+	return cfg, nil
+}
+
+func (c *config) save() error {
+	raw, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(configFile), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(configFile, raw, 0o600)
+}
+
+// selectedProfile returns the name of the profile in use: --profile, then
+// ASSETCLI_PROFILE, then the config file's profile. It is empty when none is set.
+func (c *config) selectedProfile() string {
+	if profileName != "" {
+		return profileName
+	}
+	if name := os.Getenv("ASSETCLI_PROFILE"); name != "" {
+		return name
+	}
+	return c.Profile
+}
+
+// profile returns the settings of the selected profile, failing when a profile
+// was asked for that the file does not have.
+func (c *config) profile() (string, map[string]string, error) {
+	name := c.selectedProfile()
+	if name == "" {
+		return "", nil, nil
+	}
+	settings, ok := c.Profiles[name]
+	if !ok {
+		return "", nil, fmt.Errorf("profile %q not found in %s", name, configFile)
+	}
+	return name, settings, nil
+}
+
+// applyProfile fills the flags of cmd the user did not set from the environment
+// or the selected profile.
+func applyProfile(cmd *cobra.Command) error {
+	if cmd.Parent() == configCmd {
+		return nil
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	name, settings, err := cfg.profile()
+	if err != nil {
+		return err
+	}
+	for _, setting := range profileSettings {
+		f := cmd.Flags().Lookup(setting.key)
+		if f == nil || f.Changed {
+			continue
+		}
+		value, source := os.Getenv(setting.env), setting.env
+		if value == "" {
+			value, source = settings[setting.key], fmt.Sprintf("profile %s: %s", name, setting.key)
+		}
+		if value == "" {
+			continue
+		}
+		if setting.check != nil {
+			if err := setting.check(value); err != nil {
+				return fmt.Errorf("%s: %v", source, err)
+			}
+		}
+		// set the value without marking the flag as given, so aliases such as
+		// --chain and --asset still take precedence over the profile
+		if err := f.Value.Set(value); err != nil {
+			return fmt.Errorf("%s: %v", source, err)
+		}
+	}
+	return nil
+}
+
+func findProfileSetting(key string) *profileSetting {
+	for i := range profileSettings {
+		if profileSettings[i].key == key {
+			return &profileSettings[i]
+		}
+	}
+	return nil
+}
+
+func validateUint32(value string) error {
+	_, err := strconv.ParseUint(value, 10, 32)
+	return err
+}
+
+func configInit_() error {
+	if _, err := os.Stat(configFile); err == nil {
+		return fmt.Errorf("%s already exists, change it with config set", configFile)
+	}
+	name := profileName
+	if name == "" {
+		name = "local"
+	}
+	cfg := &config{
+		Profile: name,
+		Profiles: map[string]map[string]string{
+			name: {
+				"rpcUrl":              "http://localhost:8545",
+				"apiUrl":              "http://localhost:1317",
+				"layerZeroID":         "101",
+				"deposit-precompile":  depositPrecompileAddress,
+				"delegate-precompile": delegatePrecompileAddress,
+				"reward-precompile":   rewardPrecompileAddress,
+			},
+		},
+	}
+	if err := cfg.save(); err != nil {
+		return err
+	}
+	fmt.Fprintf(textOut, "Wrote %s with profile %s\n", configFile, name)
+	return nil
+}
+
+func configShow_() error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	name, settings, err := cfg.profile()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(textOut, "Config: ", configFile)
+	if name == "" {
+		fmt.Fprintln(textOut, "Profile: none")
+	} else {
+		fmt.Fprintln(textOut, "Profile:", name)
+	}
+	resolved := make(map[string]map[string]string, len(profileSettings))
+	for _, setting := range profileSettings {
+		value, source := os.Getenv(setting.env), setting.env
+		if value == "" {
+			value, source = settings[setting.key], "profile"
+		}
+		if value == "" {
+			value, source = settingDefault(setting.key), "default"
+		}
+		resolved[setting.key] = map[string]string{"value": value, "source": source}
+		fmt.Fprintf(textOut, "  %-20s %-44s (%s)\n", setting.key, value, source)
+	}
+
+	setResult(map[string]interface{}{"config": configFile, "profile": name, "settings": resolved})
+
+	var others []string
+	for other := range cfg.Profiles {
+		if other != name {
+			others = append(others, other)
+		}
+	}
+	sort.Strings(others)
+	if len(others) > 0 {
+		fmt.Fprintln(textOut, "Other profiles:", others)
+	}
+	return nil
+}
+
+// settingDefault returns the flag default of key, looking at the first command
+// defining it for per-command flags such as --rpcUrl.
+func settingDefault(key string) string {
+	if f := rootCmd.PersistentFlags().Lookup(key); f != nil {
+		return f.DefValue
+	}
+	for _, cmd := range rootCmd.Commands() {
+		for _, sub := range append([]*cobra.Command{cmd}, cmd.Commands()...) {
+			if f := sub.Flags().Lookup(key); f != nil {
+				return f.DefValue
+			}
+		}
+	}
+	return ""
+}
+
+func configSet_(key, value string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	if key == "profile" {
+		if _, ok := cfg.Profiles[value]; !ok {
+			return fmt.Errorf("profile %q not found in %s", value, configFile)
+		}
+		cfg.Profile = value
+		if err := cfg.save(); err != nil {
+			return err
+		}
+		fmt.Fprintln(textOut, "Default profile is now", value)
+		return nil
+	}
+
+	setting := findProfileSetting(key)
+	if setting == nil {
+		keys := make([]string, len(profileSettings))
+		for i, s := range profileSettings {
+			keys[i] = s.key
+		}
+		return fmt.Errorf("unknown key %q, expected profile or one of %v", key, keys)
+	}
+	if setting.check != nil && value != "" {
+		if err := setting.check(value); err != nil {
+			return fmt.Errorf("%s: %v", key, err)
+		}
+	}
+
+	name := cfg.selectedProfile()
+	if name == "" {
+		name = "default"
+	}
+	if cfg.Profiles == nil {
+		cfg.Profiles = make(map[string]map[string]string)
+	}
+	if cfg.Profiles[name] == nil {
+		cfg.Profiles[name] = make(map[string]string)
+	}
+	if cfg.Profile == "" {
+		cfg.Profile = name
+	}
+	if value == "" {
+		delete(cfg.Profiles[name], key)
+	} else {
+		cfg.Profiles[name][key] = value
+	}
+	if err := cfg.save(); err != nil {
+		return err
+	}
+	fmt.Fprintf(textOut, "Profile %s: %s = %q\n", name, key, value)
+	return nil
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// decodedArg is a single ABI argument in a printable / JSON friendly form.
+type decodedArg struct {
+	Name  string      `json:"name"`
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+}
+
+// decodeCallArgs unpacks calldata against the method its selector points to.
+func decodeCallArgs(contractAbi abi.ABI, data []byte) (*abi.Method, []decodedArg, error) {
+	if len(data) < 4 {
+		return nil, nil, fmt.Errorf("calldata too short: %d bytes", len(data))
+	}
+	method, err := contractAbi.MethodById(data[:4])
+	if err != nil {
+		return nil, nil, err
+	}
+	values, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		return method, nil, err
+	}
+	return method, namedValues(method.Inputs, values), nil
+}
+
+func namedValues(arguments abi.Arguments, values []interface{}) []decodedArg {
+	args := make([]decodedArg, 0, len(values))
+	for i, value := range values {
+		args = append(args, decodedArg{
+			Name:  arguments[i].Name,
+			Type:  arguments[i].Type.String(),
+			Value: formatABIValue(value),
+		})
+	}
+	return args
+}
+
+// formatABIValue converts unpacked ABI values into hex strings, decimal strings,
+// maps and lists so they print and marshal readably.
+func formatABIValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return hexutil.Encode(v)
+	case *big.Int:
+		return v.String()
+	case common.Address:
+		return v.Hex()
+	case common.Hash:
+		return v.Hex()
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			raw := make([]byte, rv.Len())
+			reflect.Copy(reflect.ValueOf(raw), rv)
+			return hexutil.Encode(raw)
+		}
+		fallthrough
+	case reflect.Slice:
+		list := make([]interface{}, rv.Len())
+		for i := range list {
+			list[i] = formatABIValue(rv.Index(i).Interface())
+		}
+		return list
+	case reflect.Struct:
+		fields := make(map[string]interface{}, rv.NumField())
+		for i := 0; i < rv.NumField(); i++ {
+			field := rv.Type().Field(i)
+			name := strings.Split(field.Tag.Get("json"), ",")[0]
+			if name == "" {
+				name = field.Name
+			}
+			fields[name] = formatABIValue(rv.Field(i).Interface())
+		}
+		return fields
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return nil
+		}
+		return formatABIValue(rv.Elem().Interface())
+	}
+	return value
+}
+
+func printDecodedArgs(method *abi.Method, args []decodedArg) {
+	fmt.Fprintln(textOut, "Method:", method.Sig)
+	for _, arg := range args {
+		fmt.Fprintf(textOut, "  %s (%s): %v\n", arg.Name, arg.Type, arg.Value)
+	}
+}
+
+// revertReason extracts the Error(string) reason from a reverted call or
+// estimation. ok is false when err does not carry revert data.
+func revertReason(err error) (string, bool) {
+	var dataErr rpc.DataError
+	if !errors.As(err, &dataErr) {
+		return "", false
+	}
+	data, isString := dataErr.ErrorData().(string)
+	if !isString {
+		return "", false
+	}
+	raw, decodeErr := hexutil.Decode(data)
+	if decodeErr != nil || len(raw) == 0 {
+		return err.Error(), true
+	}
+	reason, unpackErr := abi.UnpackRevert(raw)
+	if unpackErr != nil {
+		return fmt.Sprintf("%v (revert data %s)", err, data), true
+	}
+	return reason, true
+}
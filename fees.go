@@ -0,0 +1,193 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+const (
+	txTypeLegacy  = "legacy"
+	txTypeDynamic = "1559"
+)
+
+var (
+	txType         string
+	maxFee         string
+	maxPriorityFee string
+)
+
+// txFees holds the fee fields of either a legacy or a dynamic fee transaction.
+type txFees struct {
+	Type           string   `json:"type"`
+	GasPrice       *big.Int `json:"gasPrice,omitempty"`
+	MaxFee         *big.Int `json:"maxFee,omitempty"`
+	MaxPriorityFee *big.Int `json:"maxPriorityFee,omitempty"`
+}
+
+// MarshalJSON writes the fees as decimal strings, like the decoded amounts,
+// so JSON consumers do not lose precision above 2^53.
+func (f txFees) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type           string `json:"type"`
+		GasPrice       string `json:"gasPrice,omitempty"`
+		MaxFee         string `json:"maxFee,omitempty"`
+		MaxPriorityFee string `json:"maxPriorityFee,omitempty"`
+	}{f.Type, decimalString(f.GasPrice), decimalString(f.MaxFee), decimalString(f.MaxPriorityFee)})
+}
+
+// decimalString is the base 10 form of value, or "" for nil.
+func decimalString(value *big.Int) string {
+	if value == nil {
+		return ""
+	}
+	return value.String()
+}
+
+func (f txFees) String() string {
+	if f.Type == txTypeLegacy {
+		return fmt.Sprintf("type=legacy gasPrice=%s", f.GasPrice)
+	}
+	return fmt.Sprintf("type=1559 maxFee=%s maxPriorityFee=%s", f.MaxFee, f.MaxPriorityFee)
+}
+
+func registerFeeFlags() {
+	rootCmd.PersistentFlags().StringVar(&txType, "tx-type", txTypeDynamic, "Transaction type: legacy or 1559")
+	rootCmd.PersistentFlags().StringVar(&maxFee, "max-fee", "", "Max fee per gas in wei for 1559 txs, defaults to 2*baseFee+tip")
+	rootCmd.PersistentFlags().StringVar(&maxPriorityFee, "max-priority-fee", "", "Max priority fee per gas in wei for 1559 txs, defaults to the node's suggestion")
+}
+
+func parseWei(name, value string) (*big.Int, error) {
+	wei, ok := new(big.Int).SetString(value, 10)
+	if !ok || wei.Sign() < 0 {
+		return nil, fmt.Errorf("invalid %s: %s", name, value)
+	}
+	return wei, nil
+}
+
+// resolveFees picks the transaction fees from the fee flags, asking the node for
+// whatever is not set. client is nil in --offline mode, where all fees must be given.
+func resolveFees(ctx context.Context, client *ethclient.Client) (txFees, error) {
+	kind := txType
+	// a bare --gas-price only makes sense for a legacy tx
+	if gasPrice != "" && !rootCmd.PersistentFlags().Changed("tx-type") {
+		kind = txTypeLegacy
+	}
+
+	switch kind {
+	case txTypeLegacy:
+		if maxFee != "" || maxPriorityFee != "" {
+			return txFees{}, fmt.Errorf("--max-fee and --max-priority-fee only apply to --tx-type 1559")
+		}
+		return legacyFees(ctx, client)
+	case txTypeDynamic:
+		if gasPrice != "" {
+			return txFees{}, fmt.Errorf("--gas-price only applies to --tx-type legacy, use --max-fee")
+		}
+		return dynamicFees(ctx, client)
+	}
+	return txFees{}, fmt.Errorf("unknown --tx-type %q, expected legacy or 1559", txType)
+}
+
+func legacyFees(ctx context.Context, client *ethclient.Client) (txFees, error) {
+	fees := txFees{Type: txTypeLegacy}
+	if gasPrice != "" {
+		price, err := parseWei("gas price", gasPrice)
+		if err != nil {
+			return txFees{}, err
+		}
+		fees.GasPrice = price
+		return fees, nil
+	}
+	if client == nil {
+		return txFees{}, fmt.Errorf("--gas-price is required offline")
+	}
+	price, err := client.SuggestGasPrice(ctx)
+	if err != nil {
+		return txFees{}, err
+	}
+	fees.GasPrice = price
+	return fees, nil
+}
+
+func dynamicFees(ctx context.Context, client *ethclient.Client) (txFees, error) {
+	fees := txFees{Type: txTypeDynamic}
+	if maxPriorityFee != "" {
+		tip, err := parseWei("max priority fee", maxPriorityFee)
+		if err != nil {
+			return txFees{}, err
+		}
+		fees.MaxPriorityFee = tip
+	}
+	if maxFee != "" {
+		feeCap, err := parseWei("max fee", maxFee)
+		if err != nil {
+			return txFees{}, err
+		}
+		fees.MaxFee = feeCap
+	}
+
+	if client == nil {
+		if fees.MaxFee == nil || fees.MaxPriorityFee == nil {
+			return txFees{}, fmt.Errorf("--max-fee and --max-priority-fee are required offline")
+		}
+	} else {
+		head, err := client.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return txFees{}, err
+		}
+		if head.BaseFee == nil {
+			// A legacy fallback would drop an explicit fee cap and price the tx
+			// at the suggested gas price, so only fall back without one.
+			if fees.MaxFee != nil || fees.MaxPriorityFee != nil {
+				return txFees{}, fmt.Errorf("the node reports no base fee, use --tx-type legacy with --gas-price instead of --max-fee/--max-priority-fee")
+			}
+			fmt.Fprintln(textOut, "The node reports no base fee, falling back to a legacy transaction")
+			return legacyFees(ctx, client)
+		}
+		if fees.MaxPriorityFee == nil {
+			tip, err := client.SuggestGasTipCap(ctx)
+			if err != nil {
+				return txFees{}, err
+			}
+			fees.MaxPriorityFee = tip
+		}
+		if fees.MaxFee == nil {
+			fees.MaxFee = new(big.Int).Add(new(big.Int).Mul(head.BaseFee, big.NewInt(2)), fees.MaxPriorityFee)
+		}
+	}
+
+	if fees.MaxPriorityFee.Cmp(fees.MaxFee) > 0 {
+		return txFees{}, fmt.Errorf("max priority fee %s is above max fee %s", fees.MaxPriorityFee, fees.MaxFee)
+	}
+	return fees, nil
+}
+
+// newTx builds an unsigned zero value transaction with the given fees.
+func newTx(chainID *big.Int, nonce uint64, to common.Address, gas uint64, fees txFees, data []byte) *types.Transaction {
+	if fees.Type == txTypeLegacy {
+		return types.NewTx(&types.LegacyTx{
+			Nonce:    nonce,
+			To:       &to,
+			Value:    big.NewInt(0),
+			Gas:      gas,
+			GasPrice: fees.GasPrice,
+			Data:     data,
+		})
+	}
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     nonce,
+		To:        &to,
+		Value:     big.NewInt(0),
+		Gas:       gas,
+		GasTipCap: fees.MaxPriorityFee,
+		GasFeeCap: fees.MaxFee,
+		Data:      data,
+	})
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"math/big"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// feeNode serves the fee related eth_ calls of a node with the given base fee,
+// nil for a node without EIP-1559.
+type feeNode struct {
+	baseFee *big.Int
+}
+
+func (n *feeNode) GetBlockByNumber(number string, full bool) *types.Header {
+	return &types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(0), BaseFee: n.baseFee}
+}
+
+func (n *feeNode) GasPrice() *hexutil.Big {
+	return (*hexutil.Big)(big.NewInt(3e9))
+}
+
+func (n *feeNode) MaxPriorityFeePerGas() *hexutil.Big {
+	return (*hexutil.Big)(big.NewInt(1e9))
+}
+
+func feeClient(t *testing.T, baseFee *big.Int) *ethclient.Client {
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", &feeNode{baseFee: baseFee}); err != nil {
+		t.Fatal(err)
+	}
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+	client, err := ethclient.Dial(httpServer.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(client.Close)
+	return client
+}
+
+func TestDynamicFees(t *testing.T) {
+	tests := []struct {
+		name                string
+		baseFee             *big.Int
+		maxFee, maxPriority string
+		want                txFees
+		err                 string
+	}{
+		{name: "suggested", baseFee: big.NewInt(5e9), want: txFees{Type: txTypeDynamic, MaxFee: big.NewInt(11e9), MaxPriorityFee: big.NewInt(1e9)}},
+		{name: "explicit cap", baseFee: big.NewInt(5e9), maxFee: "20000000000", want: txFees{Type: txTypeDynamic, MaxFee: big.NewInt(20e9), MaxPriorityFee: big.NewInt(1e9)}},
+		{name: "no base fee", want: txFees{Type: txTypeLegacy, GasPrice: big.NewInt(3e9)}},
+		{name: "no base fee with cap", maxFee: "20000000000", err: "no base fee"},
+		{name: "no base fee with tip", maxPriority: "2000000000", err: "no base fee"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setGlobal(t, &maxFee, tt.maxFee)
+			setGlobal(t, &maxPriorityFee, tt.maxPriority)
+			setGlobal(t, &gasPrice, "")
+			fees, err := dynamicFees(context.Background(), feeClient(t, tt.baseFee))
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("dynamicFees error = %v, want it to mention %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("dynamicFees: %v", err)
+			}
+			if fees.Type != tt.want.Type || !equalBig(fees.GasPrice, tt.want.GasPrice) || !equalBig(fees.MaxFee, tt.want.MaxFee) || !equalBig(fees.MaxPriorityFee, tt.want.MaxPriorityFee) {
+				t.Fatalf("fees = %+v, want %+v", fees, tt.want)
+			}
+		})
+	}
+}
+
+func equalBig(a, b *big.Int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Cmp(b) == 0
+}
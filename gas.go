@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+var (
+	gasLimitFlag  uint64
+	gasMultiplier float64
+)
+
+func registerGasFlags() {
+	rootCmd.PersistentFlags().Uint64Var(&gasLimitFlag, "gas-limit", 0, "Gas limit, estimated by the node when not set (500000 with --offline)")
+	rootCmd.PersistentFlags().Float64Var(&gasMultiplier, "gas-multiplier", 1.2, "Safety multiplier applied to the estimated gas")
+}
+
+// resolveGasLimit returns --gas-limit when set, otherwise the node's estimate
+// for msg scaled by --gas-multiplier.
+func resolveGasLimit(ctx context.Context, client *ethclient.Client, msg ethereum.CallMsg) (uint64, error) {
+	if gasLimitFlag != 0 {
+		return gasLimitFlag, nil
+	}
+	if gasMultiplier < 1 {
+		return 0, fmt.Errorf("--gas-multiplier must be at least 1, got %v", gasMultiplier)
+	}
+	estimated, err := client.EstimateGas(ctx, msg)
+	if err != nil && force {
+		fmt.Fprintf(textOut, "Gas estimation failed (%v), using %d because of --force\n", err, defaultGasLimit)
+		return defaultGasLimit, nil
+	}
+	if err != nil {
+		if reason, ok := revertReason(err); ok {
+			return 0, fmt.Errorf("gas estimation failed, the call reverts: %s", reason)
+		}
+		return 0, fmt.Errorf("gas estimation failed: %v", err)
+	}
+	scaled := math.Ceil(float64(estimated) * gasMultiplier)
+	if scaled > math.MaxUint64 {
+		return 0, fmt.Errorf("estimated gas %d overflows with multiplier %v", estimated, gasMultiplier)
+	}
+	return uint64(scaled), nil
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/tyler-smith/go-bip39"
+)
+
+// ethHDPath is the BIP-44 base path used by Ethereum wallets and by Exocore eth_secp256k1 keys,
+// which share coin type 60.
+const ethHDPath = "m/44'/60'/0'/0"
+
+var (
+	mnemonicFile string
+	hdPath       string
+	accountIndex uint32
+)
+
+// resolveHDPath expands the "eth" and "exocore" shortcuts to their base path.
+func resolveHDPath(path string) string {
+	switch strings.ToLower(path) {
+	case "", "eth", "ethereum", "exocore":
+		return ethHDPath
+	}
+	return path
+}
+
+// checkAccountRange makes sure count accounts from --account-index stay below
+// hdkeychain.HardenedKeyStart. The address_index step is not hardened, so a
+// larger index would silently derive a hardened child instead.
+func checkAccountRange(count uint32) error {
+	if count == 0 {
+		return nil
+	}
+	if last := uint64(accountIndex) + uint64(count) - 1; last >= hdkeychain.HardenedKeyStart {
+		return fmt.Errorf("--account-index %d plus %d accounts reaches index %d, past the last non-hardened index %d", accountIndex, count, last, hdkeychain.HardenedKeyStart-1)
+	}
+	return nil
+}
+
+func readMnemonic(path string) (string, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read mnemonic file: %v", err)
+	}
+	mnemonic := strings.Join(strings.Fields(string(raw)), " ")
+	if !bip39.IsMnemonicValid(mnemonic) {
+		return "", fmt.Errorf("invalid mnemonic in %s", path)
+	}
+	return mnemonic, nil
+}
+
+// deriveKey derives the private key at basePath/index from the BIP-39 mnemonic.
+func deriveKey(mnemonic, basePath string, index uint32) (*ecdsa.PrivateKey, string, error) {
+	fullPath := fmt.Sprintf("%s/%d", strings.TrimSuffix(resolveHDPath(basePath), "/"), index)
+	path, err := accounts.ParseDerivationPath(fullPath)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid hd path %s: %v", fullPath, err)
+	}
+	seed := bip39.NewSeed(mnemonic, "")
+	key, err := hdkeychain.NewMaster(seed, &chaincfg.MainNetParams)
+	if err != nil {
+		return nil, "", err
+	}
+	for _, n := range path {
+		key, err = key.Derive(n)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+	sk, err := key.ECPrivKey()
+	if err != nil {
+		return nil, "", err
+	}
+	return sk.ToECDSA(), path.String(), nil
+}
+
+func mnemonicSigner(path string, index uint32) (Signer, error) {
+	if index >= hdkeychain.HardenedKeyStart {
+		return nil, fmt.Errorf("--account-index %d is past the last non-hardened index %d", index, hdkeychain.HardenedKeyStart-1)
+	}
+	mnemonic, err := readMnemonic(mnemonicFile)
+	if err != nil {
+		return nil, err
+	}
+	sk, _, err := deriveKey(mnemonic, path, index)
+	if err != nil {
+		return nil, err
+	}
+	return newKeySigner(sk), nil
+}
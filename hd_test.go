@@ -0,0 +1,32 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+)
+
+func TestCheckAccountRange(t *testing.T) {
+	const hardened = hdkeychain.HardenedKeyStart
+	tests := []struct {
+		index, count uint32
+		ok           bool
+	}{
+		{0, 10, true},
+		{hardened - 1, 1, true},
+		{hardened - 10, 10, true},
+		{hardened - 10, 11, false},
+		{hardened, 1, false},
+		{0, hardened, true},
+		{0, hardened + 1, false},
+		{math.MaxUint32 - 1, 3, false},
+		{math.MaxUint32, 0, true},
+	}
+	for _, tt := range tests {
+		setGlobal(t, &accountIndex, tt.index)
+		if err := checkAccountRange(tt.count); (err == nil) != tt.ok {
+			t.Errorf("checkAccountRange(%d) from index %d: %v, want ok=%v", tt.count, tt.index, err, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/spf13/cobra"
+)
+
+// bech32HRP is the bech32 prefix of Exocore accounts and operators.
+var bech32HRP string
+
+var idCmd = &cobra.Command{
+	Use:   "id",
+	Short: "Compute the staker and asset IDs used by Exocore",
+}
+
+var stakerIDCmd = &cobra.Command{
+	Use:   "staker <address>",
+	Short: "Print the staker ID of an address on --layerZeroID and its 32-byte encoding",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		err := stakerID_(args[0])
+		if err != nil {
+			return failf("Failed to compute staker ID: %v", err)
+		}
+		return nil
+	},
+}
+
+var assetIDCmd = &cobra.Command{
+	Use:   "asset <address|nst>",
+	Short: "Print the asset ID of a token on --layerZeroID and its 32-byte encoding",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		err := assetID_(args[0])
+		if err != nil {
+			return failf("Failed to compute asset ID: %v", err)
+		}
+		return nil
+	},
+}
+
+var convertCmd = &cobra.Command{
+	Use:   "convert <0x address|bech32 address>",
+	Short: "Convert an account or operator address between 0x hex and bech32",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		err := convert_(args[0], bech32HRP)
+		if err != nil {
+			return failf("Failed to convert address: %v", err)
+		}
+		return nil
+	},
+}
+
+func registerIDCommands() {
+	rootCmd.PersistentFlags().StringVar(&bech32HRP, "hrp", "exo", "Bech32 prefix of operator and account addresses, e.g. im")
+
+	rootCmd.AddCommand(idCmd)
+	idCmd.AddCommand(stakerIDCmd)
+	idCmd.AddCommand(assetIDCmd)
+	rootCmd.AddCommand(convertCmd)
+}
+
+// normalizeOperator checks an operator address locally before it is packed, so
+// a typo fails here instead of on-chain, and returns it in canonical bech32 form.
+func normalizeOperator(operator string) (string, error) {
+	normalized, err := checkOperator(operator)
+	if err != nil {
+		return "", err
+	}
+	if common.IsHexAddress(operator) {
+		fmt.Fprintf(textOut, "Operator %s converted to %s\n", operator, normalized)
+	}
+	return normalized, nil
+}
+
+// checkOperator validates operator without printing anything. Bech32 addresses
+// must carry the --hrp prefix and a valid checksum; 0x addresses are converted.
+func checkOperator(operator string) (string, error) {
+	if operator == "" {
+		return "", fmt.Errorf("operator address is required")
+	}
+	if common.IsHexAddress(operator) {
+		return addressToBech32(bech32HRP, common.HexToAddress(operator))
+	}
+	hrp, addr, err := bech32ToAddress(operator)
+	if err != nil {
+		return "", fmt.Errorf("invalid operator address %q: %v", operator, err)
+	}
+	if hrp != bech32HRP {
+		return "", fmt.Errorf("operator address %q has prefix %q, expected %q (set --hrp for other networks)", operator, hrp, bech32HRP)
+	}
+	// re-encode so a mixed or upper case input reaches the chain in canonical form
+	return addressToBech32(hrp, addr)
+}
+
+// parseAccountAddress accepts an account as either 0x hex or bech32 with any prefix.
+func parseAccountAddress(address string) (common.Address, error) {
+	if common.IsHexAddress(address) {
+		return common.HexToAddress(address), nil
+	}
+	_, addr, err := bech32ToAddress(address)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("%q is neither a 0x address nor a bech32 address: %v", address, err)
+	}
+	return addr, nil
+}
+
+// assetID builds the <asset address>_<lzID hex> asset ID used by exocored queries.
+// "nst" stands for the native restaking virtual asset.
+func assetID(assetAddress string, clientChainID uint32) (string, error) {
+	if strings.EqualFold(assetAddress, "nst") {
+		assetAddress = nstAssetAddress
+	}
+	raw, err := assetToBytes(assetAddress)
+	if err != nil {
+		return "", err
+	}
+	if len(assetAddress) == 42 {
+		raw = raw[:common.AddressLength]
+	}
+	return hexutil.Encode(raw) + "_" + hexutil.EncodeUint64(uint64(clientChainID)), nil
+}
+
+func stakerID_(staker string) error {
+	addr, err := parseAccountAddress(staker)
+	if err != nil {
+		return err
+	}
+	id, padded := stakerID(addr, layerZeroID), hexutil.Encode(paddingAddressTo32(addr))
+	setResult(map[string]string{"stakerID": id, "bytes32": padded})
+	fmt.Fprintln(textOut, "Staker ID:", id)
+	fmt.Fprintln(textOut, "Staker bytes32:", padded)
+	return nil
+}
+
+func assetID_(asset string) error {
+	id, err := assetID(asset, layerZeroID)
+	if err != nil {
+		return err
+	}
+	if strings.EqualFold(asset, "nst") {
+		asset = nstAssetAddress
+	}
+	raw, err := assetToBytes(asset)
+	if err != nil {
+		return err
+	}
+	setResult(map[string]string{"assetID": id, "bytes32": hexutil.Encode(raw)})
+	fmt.Fprintln(textOut, "Asset ID:", id)
+	fmt.Fprintln(textOut, "Asset bytes32:", hexutil.Encode(raw))
+	return nil
+}
+
+func convert_(address, hrp string) error {
+	addr, err := parseAccountAddress(address)
+	if err != nil {
+		return err
+	}
+	bech, err := addressToBech32(hrp, addr)
+	if err != nil {
+		return err
+	}
+	padded := hexutil.Encode(paddingAddressTo32(addr))
+	setResult(map[string]string{"hex": addr.Hex(), "bech32": bech, "bytes32": padded})
+	fmt.Fprintln(textOut, "Hex:", addr.Hex())
+	fmt.Fprintln(textOut, "Bech32:", bech)
+	fmt.Fprintln(textOut, "Bytes32:", padded)
+	return nil
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/cobra"
+)
+
+var keysCmd = &cobra.Command{
+	Use:   "keys",
+	Short: "Manage encrypted keystore keys",
+}
+
+var keysNewCmd = &cobra.Command{
+	Use:   "new",
+	Short: "Create a new key in the keystore",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, _ := cmd.Flags().GetString("name")
+		address, err := keysNew_(name)
+		if err != nil {
+			return failf("Failed to create key: %v", err)
+		}
+		setResult(map[string]string{"name": name, "address": address.Hex()})
+		fmt.Fprintln(textOut, "Created key:", address.Hex())
+		return nil
+	},
+}
+
+var keysImportCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import a hex private key into the keystore",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, _ := cmd.Flags().GetString("name")
+		keyFile, _ := cmd.Flags().GetString("key-file")
+		address, err := keysImport_(name, keyFile)
+		if err != nil {
+			return failf("Failed to import key: %v", err)
+		}
+		setResult(map[string]string{"name": name, "address": address.Hex()})
+		fmt.Fprintln(textOut, "Imported key:", address.Hex())
+		return nil
+	},
+}
+
+var keysListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List keys in the keystore",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := keysList_(); err != nil {
+			return failf("Failed to list keys: %v", err)
+		}
+		return nil
+	},
+}
+
+var keysExportAddressCmd = &cobra.Command{
+	Use:   "export-address <name|address>",
+	Short: "Print the address of a keystore key",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		account, err := findKeystoreAccount(openKeystore(), args[0])
+		if err != nil {
+			return failf("Failed to export address: %v", err)
+		}
+		setResult(map[string]string{"address": account.Address.Hex()})
+		fmt.Fprintln(textOut, account.Address.Hex())
+		return nil
+	},
+}
+
+var keysDeriveCmd = &cobra.Command{
+	Use:   "derive",
+	Short: "Print the accounts derived from --mnemonic-file",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		count, _ := cmd.Flags().GetUint32("count")
+		if err := keysDerive_(count); err != nil {
+			return failf("Failed to derive keys: %v", err)
+		}
+		return nil
+	},
+}
+
+func registerKeysCommands() {
+	rootCmd.AddCommand(keysCmd)
+	keysCmd.AddCommand(keysNewCmd)
+	keysCmd.AddCommand(keysImportCmd)
+	keysCmd.AddCommand(keysListCmd)
+	keysCmd.AddCommand(keysExportAddressCmd)
+	keysCmd.AddCommand(keysDeriveCmd)
+
+	keysNewCmd.Flags().String("name", "", "Name to refer to the key with --from")
+
+	keysImportCmd.Flags().String("name", "", "Name to refer to the key with --from")
+	keysImportCmd.Flags().String("key-file", "", "File holding the hex private key, defaults to ASSETCLI_PRIVATE_KEY or a prompt")
+
+	keysDeriveCmd.Flags().Uint32("count", 1, "Number of accounts to derive, starting at --account-index")
+}
+
+func keysNew_(name string) (common.Address, error) {
+	passphrase, err := readPassphrase("New passphrase: ", true)
+	if err != nil {
+		return common.Address{}, err
+	}
+	account, err := openKeystore().NewAccount(passphrase)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if name != "" {
+		if err := saveKeyName(name, account.Address); err != nil {
+			return common.Address{}, err
+		}
+	}
+	return account.Address, nil
+}
+
+func keysImport_(name, keyFile string) (common.Address, error) {
+	sk, err := readImportKey(keyFile)
+	if err != nil {
+		return common.Address{}, err
+	}
+	passphrase, err := readPassphrase("New passphrase: ", true)
+	if err != nil {
+		return common.Address{}, err
+	}
+	address := crypto.PubkeyToAddress(sk.PublicKey)
+	_, err = openKeystore().ImportECDSA(sk, passphrase)
+	if err != nil && !errors.Is(err, keystore.ErrAccountAlreadyExists) {
+		return common.Address{}, err
+	}
+	if name != "" {
+		if err := saveKeyName(name, address); err != nil {
+			return common.Address{}, err
+		}
+	}
+	return address, nil
+}
+
+func readImportKey(keyFile string) (*ecdsa.PrivateKey, error) {
+	var key string
+	switch {
+	case keyFile != "":
+		raw, err := os.ReadFile(keyFile)
+		if err != nil {
+			return nil, err
+		}
+		key = string(raw)
+	case os.Getenv(privateKeyEnv) != "":
+		key = os.Getenv(privateKeyEnv)
+	default:
+		prompted, err := promptHidden("Private key (hex): ")
+		if err != nil {
+			return nil, err
+		}
+		key = prompted
+	}
+	return crypto.HexToECDSA(strings.TrimPrefix(strings.TrimSpace(key), "0x"))
+}
+
+func keysList_() error {
+	names, err := loadKeyNames()
+	if err != nil {
+		return err
+	}
+	byAddress := make(map[common.Address]string, len(names))
+	for name, address := range names {
+		byAddress[common.HexToAddress(address)] = name
+	}
+	var keys []map[string]string
+	for _, account := range openKeystore().Accounts() {
+		name := byAddress[account.Address]
+		keys = append(keys, map[string]string{"name": name, "address": account.Address.Hex(), "path": account.URL.Path})
+		if name == "" {
+			name = "-"
+		}
+		fmt.Fprintf(textOut, "%-16s %s %s\n", name, account.Address.Hex(), account.URL.Path)
+	}
+	setResult(keys)
+	return nil
+}
+
+func keysDerive_(count uint32) error {
+	if mnemonicFile == "" {
+		return fmt.Errorf("--mnemonic-file is required")
+	}
+	if err := checkAccountRange(count); err != nil {
+		return err
+	}
+	mnemonic, err := readMnemonic(mnemonicFile)
+	if err != nil {
+		return err
+	}
+	var accounts []map[string]interface{}
+	for n := uint32(0); n < count; n++ {
+		i := accountIndex + n
+		sk, path, err := deriveKey(mnemonic, hdPath, i)
+		if err != nil {
+			return err
+		}
+		address := crypto.PubkeyToAddress(sk.PublicKey)
+		exoAddress, err := addressToBech32("exo", address)
+		if err != nil {
+			return err
+		}
+		accounts = append(accounts, map[string]interface{}{"index": i, "path": path, "address": address.Hex(), "bech32": exoAddress, "stakerID": stakerID(address, layerZeroID)})
+		fmt.Fprintf(textOut, "%d %s %s %s %s\n", i, path, address.Hex(), exoAddress, stakerID(address, layerZeroID))
+	}
+	setResult(accounts)
+	return nil
+}
@@ -2,21 +2,19 @@ package main
 
 import (
 	"context"
-	"crypto/ecdsa"
-	"encoding/hex"
+	"errors"
 	"fmt"
-	"log"
 	"math/big"
 	"strings"
-	"time"
+	"sync"
 
+	"github.com/btcsuite/btcd/btcutil/bech32"
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/spf13/cobra"
@@ -1151,6 +1149,14 @@ const (
 ]
 `
 
+	// nstAssetAddress is the virtual asset native restaked ETH is accounted under
+	nstAssetAddress = "0xeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee"
+
+	defaultGasLimit = 500000
+)
+
+// precompile addresses, overridable per network with the --*-precompile flags or a profile
+var (
 	depositPrecompileAddress  = "0x0000000000000000000000000000000000000804"
 	delegatePrecompileAddress = "0x0000000000000000000000000000000000000805"
 	rewardPrecompileAddress   = "0x0000000000000000000000000000000000000806"
@@ -1171,75 +1177,79 @@ var rootCmd = &cobra.Command{
 var depositCmd = &cobra.Command{
 	Use:   "deposit",
 	Short: "Deposit to Exocore",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		rpcUrl, _ := cmd.Flags().GetString("rpcUrl")
 		staker, _ := cmd.Flags().GetString("staker")
 		amountStr, _ := cmd.Flags().GetString("amount")
-		amount, ok := new(big.Int).SetString(amountStr, 10)
-		if !ok {
-			log.Fatalf("Invalid amount: %s", amountStr)
+		amount, err := parseAmount(amountStr, lookupAsset(defaultAssetID, layerZeroID))
+		if err != nil {
+			return failf("Invalid amount: %v", err)
 		}
-		err := deposit_(rpcUrl, staker, amount)
+		err = deposit_(rpcUrl, staker, amount)
 		if err != nil {
-			log.Fatalf("Failed to deposit: %v", err)
+			return failf("Failed to deposit: %v", err)
 		}
+		return nil
 	},
 }
 
 var depositNSTCmd = &cobra.Command{
 	Use:   "depositNST",
 	Short: "DepositNST to Exocore",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		rpcUrl, _ := cmd.Flags().GetString("rpcUrl")
 		staker, _ := cmd.Flags().GetString("staker")
 		amountStr, _ := cmd.Flags().GetString("amount")
-		amount, ok := new(big.Int).SetString(amountStr, 10)
+		amount, err := parseAmount(amountStr, lookupAsset(nstAssetAddress, layerZeroID))
 		pubkey, _ := cmd.Flags().GetString("pubkey")
-		if !ok {
-			log.Fatalf("Invalid amount: %s", amountStr)
+		if err != nil {
+			return failf("Invalid amount: %v", err)
 		}
-		err := depositNST_(rpcUrl, pubkey, staker, amount)
+		err = depositNST_(rpcUrl, pubkey, staker, amount)
 		if err != nil {
-			log.Fatalf("Failed to depositNST: %v", err)
+			return failf("Failed to depositNST: %v", err)
 		}
+		return nil
 	},
 }
 
 var withdrawNSTCmd = &cobra.Command{
 	Use:   "withdrawNST",
 	Short: "WithdrawNST to Exocore",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		rpcUrl, _ := cmd.Flags().GetString("rpcUrl")
 		staker, _ := cmd.Flags().GetString("staker")
 		amountStr, _ := cmd.Flags().GetString("amount")
-		amount, ok := new(big.Int).SetString(amountStr, 10)
+		amount, err := parseAmount(amountStr, lookupAsset(nstAssetAddress, layerZeroID))
 		pubkey, _ := cmd.Flags().GetString("pubkey")
-		if !ok {
-			log.Fatalf("Invalid amount: %s", amountStr)
+		if err != nil {
+			return failf("Invalid amount: %v", err)
 		}
-		err := withdrawNST_(rpcUrl, pubkey, staker, amount)
+		err = withdrawNST_(rpcUrl, pubkey, staker, amount)
 		if err != nil {
-			log.Fatalf("Failed to withdrawNST: %v", err)
+			return failf("Failed to withdrawNST: %v", err)
 		}
+		return nil
 	},
 }
 
 var delegateCmd = &cobra.Command{
 	Use:   "delegate",
 	Short: "Delegate to Exocore",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		rpcUrl, _ := cmd.Flags().GetString("rpcUrl")
 		staker, _ := cmd.Flags().GetString("staker")
 		operator, _ := cmd.Flags().GetString("operator")
 		amountStr, _ := cmd.Flags().GetString("amount")
-		amount, ok := new(big.Int).SetString(amountStr, 10)
-		if !ok {
-			log.Fatalf("Invalid amount: %s", amountStr)
+		amount, err := parseAmount(amountStr, lookupAsset(defaultAssetID, layerZeroID))
+		if err != nil {
+			return failf("Invalid amount: %v", err)
 		}
-		err := delegateTo_(rpcUrl, staker, operator, amount)
+		err = delegateTo_(rpcUrl, staker, operator, amount)
 		if err != nil {
-			log.Fatalf("Failed to delegate: %v", err)
+			return failf("Failed to delegate: %v", err)
 		}
+		return nil
 	},
 }
 
@@ -1247,85 +1257,91 @@ var delegateCmd = &cobra.Command{
 var undelegateCmd = &cobra.Command{
 	Use:   "undelegate",
 	Short: "Undelegate from Exocore",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		rpcUrl, _ := cmd.Flags().GetString("rpcUrl")
 		staker, _ := cmd.Flags().GetString("staker")
 		operator, _ := cmd.Flags().GetString("operator")
 		amountStr, _ := cmd.Flags().GetString("amount")
-		amount, ok := new(big.Int).SetString(amountStr, 10)
+		amount, err := parseAmount(amountStr, lookupAsset(defaultAssetID, layerZeroID))
 		instantUnbond, _ := cmd.Flags().GetBool("instantUnbond")
-		if !ok {
-			log.Fatalf("Invalid amount: %s", amountStr)
+		if err != nil {
+			return failf("Invalid amount: %v", err)
 		}
-		err := undelegate_(rpcUrl, staker, operator, amount, instantUnbond)
+		err = undelegate_(rpcUrl, staker, operator, amount, instantUnbond)
 		if err != nil {
-			log.Fatalf("Failed to undelegate: %v", err)
+			return failf("Failed to undelegate: %v", err)
 		}
+		return nil
 	},
 }
 
 var selfDelegateCmd = &cobra.Command{
 	Use:   "self-delegate",
 	Short: "Self delegate to Exocore",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		rpcUrl, _ := cmd.Flags().GetString("rpcUrl")
 		staker, _ := cmd.Flags().GetString("staker")
 		operator, _ := cmd.Flags().GetString("operator")
-		err := selfDelegate_(rpcUrl, staker[2:], operator)
+		err := selfDelegate_(rpcUrl, staker, operator)
 		if err != nil {
-			log.Fatalf("Failed to self delegate: %v", err)
+			return failf("Failed to self delegate: %v", err)
 		}
+		return nil
 	},
 }
 
 var cancelSelfDelegateCmd = &cobra.Command{
 	Use:   "cancel-self-delegate",
 	Short: "Cancel self delegate to Exocore",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		rpcUrl, _ := cmd.Flags().GetString("rpcUrl")
 		staker, _ := cmd.Flags().GetString("staker")
-		err := cancelSelfDelegate_(rpcUrl, staker[2:])
+		err := cancelSelfDelegate_(rpcUrl, staker)
 		if err != nil {
-			log.Fatalf("Failed to cancel self delegate: %v", err)
+			return failf("Failed to cancel self delegate: %v", err)
 		}
+		return nil
 	},
 }
 
 var registerTokenCmd = &cobra.Command{
 	Use:   "register-token",
 	Short: "Register token to Exocore",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		rpcUrl, _ := cmd.Flags().GetString("rpcUrl")
 		assetAddress, _ := cmd.Flags().GetString("assetAddress")
 		decimals, _ := cmd.Flags().GetUint8("decimals")
 		name, _ := cmd.Flags().GetString("name")
+		symbol, _ := cmd.Flags().GetString("symbol")
 		metaData, _ := cmd.Flags().GetString("metaData")
 		oracleInfo, _ := cmd.Flags().GetString("oracleInfo")
-		err := registerToken_(rpcUrl, assetAddress, decimals, name, metaData, oracleInfo)
+		err := registerToken_(rpcUrl, assetAddress, decimals, name, symbol, metaData, oracleInfo)
 		if err != nil {
-			log.Fatalf("Failed to register token: %v", err)
+			return failf("Failed to register token: %v", err)
 		}
+		return nil
 	},
 }
 
 var updateTokenCmd = &cobra.Command{
 	Use:   "update-token",
 	Short: "Update token to Exocore",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		rpcUrl, _ := cmd.Flags().GetString("rpcUrl")
 		assetAddress, _ := cmd.Flags().GetString("assetAddress")
 		metaData, _ := cmd.Flags().GetString("metaData")
 		err := updateToken_(rpcUrl, assetAddress, metaData)
 		if err != nil {
-			log.Fatalf("Failed to update token: %v", err)
+			return failf("Failed to update token: %v", err)
 		}
+		return nil
 	},
 }
 
 var registerOrUpdateClientChainCmd = &cobra.Command{
 	Use:   "register-or-update-client-chain",
 	Short: "Register or update client chain to Exocore",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		rpcUrl, _ := cmd.Flags().GetString("rpcUrl")
 		clientChainID, _ := cmd.Flags().GetUint32("clientChainID")
 		addressLength, _ := cmd.Flags().GetUint8("addressLength")
@@ -1334,68 +1350,73 @@ var registerOrUpdateClientChainCmd = &cobra.Command{
 		signatureType, _ := cmd.Flags().GetString("signatureType")
 		err := registerOrUpdateClientChain_(rpcUrl, clientChainID, addressLength, name, metaInfo, signatureType)
 		if err != nil {
-			log.Fatalf("Failed to register or update client chain: %v", err)
+			return failf("Failed to register or update client chain: %v", err)
 		}
+		return nil
 	},
 }
 
 var claimRewardCmd = &cobra.Command{
 	Use:   "claim-reward",
 	Short: "Claim reward from Exocore",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		rpcUrl, _ := cmd.Flags().GetString("rpcUrl")
 		clientChainID, _ := cmd.Flags().GetUint32("clientChainID")
 		staker, _ := cmd.Flags().GetString("staker")
 		err := claimReward_(rpcUrl, clientChainID, staker)
 		if err != nil {
-			log.Fatalf("Failed to claim reward: %v", err)
+			return failf("Failed to claim reward: %v", err)
 		}
+		return nil
 	},
 }
 
 var fundAVSRewardCmd = &cobra.Command{
 	Use:   "fund-avs-reward",
 	Short: "Fund AVS reward to Exocore",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		rpcUrl, _ := cmd.Flags().GetString("rpcUrl")
 		rewardAssetChainID, _ := cmd.Flags().GetUint32("rewardAssetChainID")
 		avsAddress, _ := cmd.Flags().GetString("avsAddress")
 		assetAddress, _ := cmd.Flags().GetString("assetAddress")
 		amountStr, _ := cmd.Flags().GetString("amount")
-		amount, ok := new(big.Int).SetString(amountStr, 10)
-		if !ok {
-			log.Fatalf("Invalid amount: %s", amountStr)
+		amount, err := parseAmount(amountStr, lookupAsset(assetAddress, rewardAssetChainID))
+		if err != nil {
+			return failf("Invalid amount: %v", err)
 		}
-		err := fundAVSReward_(rpcUrl, rewardAssetChainID, avsAddress, assetAddress, amount)
+		err = fundAVSReward_(rpcUrl, rewardAssetChainID, avsAddress, assetAddress, amount)
 		if err != nil {
-			log.Fatalf("Failed to fund AVS reward: %v", err)
+			return failf("Failed to fund AVS reward: %v", err)
 		}
+		return nil
 	},
 }
 
 var isRegisteredRewardTokenCmd = &cobra.Command{
 	Use:   "is-registered-reward-token",
 	Short: "Check if reward token is registered in Exocore",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		rpcUrl, _ := cmd.Flags().GetString("rpcUrl")
 		clientChainID, _ := cmd.Flags().GetUint32("clientChainID")
 		token, _ := cmd.Flags().GetString("token")
 		registered, err := isRegisteredRewardToken_(rpcUrl, clientChainID, token)
 		if err != nil {
-			log.Fatalf("Failed to check if reward token is registered: %v", err)
+			return failf("Failed to check if reward token is registered: %v", err)
 		}
+		setResult(map[string]interface{}{"clientChainID": clientChainID, "token": token, "registered": registered})
 		if registered {
-			fmt.Println("Reward token is registered")
+			fmt.Fprintln(textOut, "Reward token is registered")
 		} else {
-			fmt.Println("Reward token is not registered")
+			fmt.Fprintln(textOut, "Reward token is not registered")
 		}
+		return nil
 	},
 }
 
 var registerRewardTokenCmd = &cobra.Command{
 	Use:   "register-reward-token",
 	Short: "Register reward token to Exocore",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		rpcUrl, _ := cmd.Flags().GetString("rpcUrl")
 		clientChainID, _ := cmd.Flags().GetUint32("clientChainID")
 		token, _ := cmd.Flags().GetString("token")
@@ -1407,212 +1428,231 @@ var registerRewardTokenCmd = &cobra.Command{
 		denominationExponent, _ := cmd.Flags().GetUint8("denominationExponent")
 		err := registerRewardToken_(rpcUrl, clientChainID, token, decimals, name, symbol, metaData, denomination, denominationExponent)
 		if err != nil {
-			log.Fatalf("Failed to register reward token: %v", err)
+			return failf("Failed to register reward token: %v", err)
 		}
+		return nil
 	},
 }
 
 var setAVSEpochRewardCmd = &cobra.Command{
 	Use:   "set-avs-epoch-reward",
 	Short: "Set AVS epoch reward in Exocore",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		rpcUrl, _ := cmd.Flags().GetString("rpcUrl")
 		denomination, _ := cmd.Flags().GetString("denomination")
 		amountStr, _ := cmd.Flags().GetString("amount")
-		amount, ok := new(big.Int).SetString(amountStr, 10)
-		if !ok {
-			log.Fatalf("Invalid amount: %s", amountStr)
+		amount, err := parseAmount(amountStr, nil)
+		if err != nil {
+			return failf("Invalid amount: %v", err)
 		}
-		err := setAVSEpochReward_(rpcUrl, denomination, amount)
+		err = setAVSEpochReward_(rpcUrl, denomination, amount)
 		if err != nil {
-			log.Fatalf("Failed to set AVS epoch reward: %v", err)
+			return failf("Failed to set AVS epoch reward: %v", err)
 		}
+		return nil
 	},
 }
 
 var setAVSRewardParamsCmd = &cobra.Command{
 	Use:   "set-avs-reward-params",
 	Short: "Set AVS reward params in Exocore",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		rpcUrl, _ := cmd.Flags().GetString("rpcUrl")
 		isCustomRewardInflation, _ := cmd.Flags().GetBool("isCustomRewardInflation")
 		isCustomOperatorRatio, _ := cmd.Flags().GetBool("isCustomOperatorRatio")
 		err := setAVSRewardParams_(rpcUrl, isCustomRewardInflation, isCustomOperatorRatio)
 		if err != nil {
-			log.Fatalf("Failed to set AVS reward params: %v", err)
+			return failf("Failed to set AVS reward params: %v", err)
 		}
+		return nil
 	},
 }
 
 var setOperatorRewardProportionsCmd = &cobra.Command{
 	Use:   "set-operator-reward-proportions",
 	Short: "Set operator reward proportions in Exocore",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		rpcUrl, _ := cmd.Flags().GetString("rpcUrl")
 		operator, _ := cmd.Flags().GetString("operator")
 		numeratorStr, _ := cmd.Flags().GetString("numerator")
 		denominatorStr, _ := cmd.Flags().GetString("denominator")
-		numerator, ok := new(big.Int).SetString(numeratorStr, 10)
-		if !ok {
-			log.Fatalf("Invalid numerator: %s", numeratorStr)
+		numerator, err := parseAmount(numeratorStr, nil)
+		if err != nil {
+			return failf("Invalid numerator: %v", err)
 		}
-		denominator, ok := new(big.Int).SetString(denominatorStr, 10)
-		if !ok {
-			log.Fatalf("Invalid denominator: %s", denominatorStr)
+		denominator, err := parseAmount(denominatorStr, nil)
+		if err != nil {
+			return failf("Invalid denominator: %v", err)
 		}
-		err := setOperatorRewardProportions_(rpcUrl, operator, numerator, denominator)
+		err = setOperatorRewardProportions_(rpcUrl, operator, numerator, denominator)
 		if err != nil {
-			log.Fatalf("Failed to set operator reward proportions: %v", err)
+			return failf("Failed to set operator reward proportions: %v", err)
 		}
+		return nil
 	},
 }
 
 var setStakerRewardParamsCmd = &cobra.Command{
 	Use:   "set-staker-reward-params",
 	Short: "Set staker reward params in Exocore",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		rpcUrl, _ := cmd.Flags().GetString("rpcUrl")
 		staker, _ := cmd.Flags().GetString("staker")
 		redelegateReward, _ := cmd.Flags().GetBool("redelegateReward")
 		redelegateOperator, _ := cmd.Flags().GetString("redelegateOperator")
 		err := setStakerRewardParams_(rpcUrl, layerZeroID, staker, redelegateReward, redelegateOperator)
 		if err != nil {
-			log.Fatalf("Failed to set staker reward params: %v", err)
+			return failf("Failed to set staker reward params: %v", err)
 		}
+		return nil
 	},
 }
 
 var undelegateRewardCmd = &cobra.Command{
 	Use:   "undelegate-reward",
 	Short: "Undelegate reward from Exocore",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		rpcUrl, _ := cmd.Flags().GetString("rpcUrl")
 		rewardAssetChainID, _ := cmd.Flags().GetUint32("rewardAssetChainID")
 		staker, _ := cmd.Flags().GetString("staker")
 		operator, _ := cmd.Flags().GetString("operator")
 		amountStr, _ := cmd.Flags().GetString("amount")
-		amount, ok := new(big.Int).SetString(amountStr, 10)
+		amount, err := parseAmount(amountStr, lookupAsset(defaultAssetID, rewardAssetChainID))
 		instantUnbond, _ := cmd.Flags().GetBool("instantUnbond")
-		if !ok {
-			log.Fatalf("Invalid amount: %s", amountStr)
+		if err != nil {
+			return failf("Invalid amount: %v", err)
 		}
-		err := undelegateReward_(rpcUrl, layerZeroID, rewardAssetChainID, staker, operator, amount, instantUnbond)
+		err = undelegateReward_(rpcUrl, layerZeroID, rewardAssetChainID, staker, operator, amount, instantUnbond)
 		if err != nil {
-			log.Fatalf("Failed to undelegate reward: %v", err)
+			return failf("Failed to undelegate reward: %v", err)
 		}
+		return nil
 	},
 }
 
 var updateRewardTokenCmd = &cobra.Command{
 	Use:   "update-reward-token",
 	Short: "Update reward token in Exocore",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		rpcUrl, _ := cmd.Flags().GetString("rpcUrl")
 		token, _ := cmd.Flags().GetString("token")
 		metaData, _ := cmd.Flags().GetString("metaData")
 		err := updateRewardToken_(rpcUrl, layerZeroID, token, metaData)
 		if err != nil {
-			log.Fatalf("Failed to update reward token: %v", err)
+			return failf("Failed to update reward token: %v", err)
 		}
+		return nil
 	},
 }
 
 var withdrawCommissionCmd = &cobra.Command{
 	Use:   "withdraw-commission",
 	Short: "Withdraw commission from Exocore",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		rpcUrl, _ := cmd.Flags().GetString("rpcUrl")
 		rewardAssetChainID, _ := cmd.Flags().GetUint32("rewardAssetChainID")
 		operator, _ := cmd.Flags().GetString("operator")
 		amountStr, _ := cmd.Flags().GetString("amount")
-		amount, ok := new(big.Int).SetString(amountStr, 10)
-		if !ok {
-			log.Fatalf("Invalid amount: %s", amountStr)
+		amount, err := parseAmount(amountStr, lookupAsset(defaultAssetID, rewardAssetChainID))
+		if err != nil {
+			return failf("Invalid amount: %v", err)
 		}
-		err := withdrawCommission_(rpcUrl, rewardAssetChainID, operator, amount)
+		err = withdrawCommission_(rpcUrl, rewardAssetChainID, operator, amount)
 		if err != nil {
-			log.Fatalf("Failed to withdraw commission: %v", err)
+			return failf("Failed to withdraw commission: %v", err)
 		}
+		return nil
 	},
 }
 
 var withdrawIMUATokenCommissionCmd = &cobra.Command{
 	Use:   "withdraw-imua-token-commission",
 	Short: "Withdraw IMUA token commission from Exocore",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		rpcUrl, _ := cmd.Flags().GetString("rpcUrl")
 		operator, _ := cmd.Flags().GetString("operator")
 		receiptAddress, _ := cmd.Flags().GetString("receiptAddress")
 		amountStr, _ := cmd.Flags().GetString("amount")
-		amount, ok := new(big.Int).SetString(amountStr, 10)
-		if !ok {
-			log.Fatalf("Invalid amount: %s", amountStr)
+		amount, err := parseAmount(amountStr, &imuaAsset)
+		if err != nil {
+			return failf("Invalid amount: %v", err)
 		}
-		err := withdrawIMUATokenCommission_(rpcUrl, operator, receiptAddress, amount)
+		err = withdrawIMUATokenCommission_(rpcUrl, operator, receiptAddress, amount)
 		if err != nil {
-			log.Fatalf("Failed to withdraw IMUA token commission: %v", err)
+			return failf("Failed to withdraw IMUA token commission: %v", err)
 		}
+		return nil
 	},
 }
 
 var withdrawIMUATokenRewardCmd = &cobra.Command{
 	Use:   "withdraw-imua-token-reward",
 	Short: "Withdraw IMUA token reward from Exocore",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		rpcUrl, _ := cmd.Flags().GetString("rpcUrl")
 		staker, _ := cmd.Flags().GetString("staker")
 		receiptAddress, _ := cmd.Flags().GetString("receiptAddress")
 		amountStr, _ := cmd.Flags().GetString("amount")
-		amount, ok := new(big.Int).SetString(amountStr, 10)
-		if !ok {
-			log.Fatalf("Invalid amount: %s", amountStr)
+		amount, err := parseAmount(amountStr, &imuaAsset)
+		if err != nil {
+			return failf("Invalid amount: %v", err)
 		}
-		err := withdrawIMUATokenReward_(rpcUrl, layerZeroID, staker, receiptAddress, amount)
+		err = withdrawIMUATokenReward_(rpcUrl, layerZeroID, staker, receiptAddress, amount)
 		if err != nil {
-			log.Fatalf("Failed to withdraw IMUA token reward: %v", err)
+			return failf("Failed to withdraw IMUA token reward: %v", err)
 		}
+		return nil
 	},
 }
 
 var withdrawRewardCmd = &cobra.Command{
 	Use:   "withdraw-reward",
 	Short: "Withdraw reward from Exocore",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		rpcUrl, _ := cmd.Flags().GetString("rpcUrl")
 		rewardAssetChainID, _ := cmd.Flags().GetUint32("rewardAssetChainID")
 		staker, _ := cmd.Flags().GetString("staker")
 		amountStr, _ := cmd.Flags().GetString("amount")
-		amount, ok := new(big.Int).SetString(amountStr, 10)
-		if !ok {
-			log.Fatalf("Invalid amount: %s", amountStr)
+		amount, err := parseAmount(amountStr, lookupAsset(defaultAssetID, rewardAssetChainID))
+		if err != nil {
+			return failf("Invalid amount: %v", err)
 		}
-		err := withdrawReward_(rpcUrl, layerZeroID, rewardAssetChainID, staker, amount)
+		err = withdrawReward_(rpcUrl, layerZeroID, rewardAssetChainID, staker, amount)
 		if err != nil {
-			log.Fatalf("Failed to withdraw reward: %v", err)
+			return failf("Failed to withdraw reward: %v", err)
 		}
+		return nil
 	},
 }
 
 var withdrawLSTCmd = &cobra.Command{
 	Use:   "withdraw",
 	Short: "WithdrawLST from Exocore",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		rpcUrl, _ := cmd.Flags().GetString("rpcUrl")
 		staker, _ := cmd.Flags().GetString("staker")
 		amountStr, _ := cmd.Flags().GetString("amount")
-		amount, ok := new(big.Int).SetString(amountStr, 10)
-		if !ok {
-			log.Fatalf("Invalid amount: %s", amountStr)
+		amount, err := parseAmount(amountStr, lookupAsset(defaultAssetID, layerZeroID))
+		if err != nil {
+			return failf("Invalid amount: %v", err)
 		}
-		err := withdrawLST_(rpcUrl, staker, amount)
+		err = withdrawLST_(rpcUrl, staker, amount)
 		if err != nil {
-			log.Fatalf("Failed to withdrawfe: %v", err)
+			return failf("Failed to withdrawfe: %v", err)
 		}
+		return nil
 	},
 }
 
 func main() {
-	rootCmd.PersistentFlags().StringVar(&privateKey, "privateKey", "", "Private key for transactions")
+	rootCmd.PersistentFlags().StringVar(&privateKey, "privateKey", "", "Private key for transactions (deprecated, prefer --from or ASSETCLI_PRIVATE_KEY)")
+	rootCmd.PersistentFlags().StringVar(&from, "from", "", "Keystore key name or address to sign with")
+	rootCmd.PersistentFlags().StringVar(&keystoreDir, "keystore", defaultKeystoreDir(), "Keystore directory")
+	rootCmd.PersistentFlags().StringVar(&passphraseFile, "passphrase-file", "", "File holding the keystore passphrase")
+	rootCmd.PersistentFlags().StringVar(&signerBackend, "signer", "local", "Signer backend: local, clef:<url> or an http(s) signing endpoint")
+	rootCmd.PersistentFlags().StringVar(&mnemonicFile, "mnemonic-file", "", "File holding a BIP-39 mnemonic to derive the signing key from")
+	rootCmd.PersistentFlags().StringVar(&hdPath, "hd-path", ethHDPath, "HD base path for --mnemonic-file, or eth / exocore")
+	rootCmd.PersistentFlags().Uint32Var(&accountIndex, "account-index", 0, "HD account index appended to --hd-path")
 	rootCmd.PersistentFlags().StringVar(&defaultAssetID, "defaultAssetID", "", "Default asset ID")
 	rootCmd.PersistentFlags().Uint32Var(&layerZeroID, "layerZeroID", 101, "LayerZero ID")
 
@@ -1644,6 +1684,36 @@ func main() {
 	rootCmd.AddCommand(withdrawIMUATokenRewardCmd)
 	rootCmd.AddCommand(withdrawRewardCmd)
 
+	// key management commands
+	registerKeysCommands()
+	// offline signing and broadcast
+	registerOfflineCommands()
+	registerBuildOnlyFlags()
+	registerFeeFlags()
+	registerGasFlags()
+	registerSimulationFlags()
+	registerNonceFlags()
+	// replacing stuck transactions
+	registerTxCommands()
+	registerWaitFlags()
+	// read-only precompile queries
+	registerQueryCommands()
+	registerAPIQueryCommands()
+	// identifier and address conversion helpers
+	registerIDCommands()
+	// token registry and human readable amounts
+	registerRegistryCommands()
+	registerAmountFlags()
+	// network profiles from ~/.assetcli/config.yaml
+	registerConfigCommands()
+	// --output json and --verbose
+	registerOutputFlags()
+	// plan files run in one process
+	registerPlanCommands()
+	registerBulkCommands()
+	registerBenchCommands()
+	registerCallCommands()
+
 	depositCmd.Flags().String("rpcUrl", "http://localhost:8545", "Exocore RPC URL")
 	depositCmd.Flags().String("staker", "", "Staker address")
 	depositCmd.Flags().String("amount", "0", "Amount to deposit")
@@ -1684,6 +1754,7 @@ func main() {
 	registerTokenCmd.Flags().String("assetAddress", "", "Asset address")
 	registerTokenCmd.Flags().Uint8("decimals", 0, "Decimals")
 	registerTokenCmd.Flags().String("name", "", "Token name")
+	registerTokenCmd.Flags().String("symbol", "", "Token symbol, only recorded in the local registry")
 	registerTokenCmd.Flags().String("metaData", "", "Meta data")
 	registerTokenCmd.Flags().String("oracleInfo", "", "Oracle info")
 
@@ -1769,295 +1840,201 @@ func main() {
 	withdrawRewardCmd.Flags().String("staker", "", "Staker address")
 	withdrawRewardCmd.Flags().String("amount", "0", "Amount to withdraw")
 
+	// required flags and input checks shared by all commands
+	registerValidation()
+
 	if err := rootCmd.Execute(); err != nil {
-		log.Fatalf("Error executing command: %v", err)
+		var failed *commandError
+		if errors.As(err, &failed) {
+			fatalf("%v", err)
+		}
+		fatalf("Error executing command: %v", executeError(err))
 	}
+	finishReport()
 }
 
 func deposit_(rpcUrl, stakerAddress string, amount *big.Int) error {
-	depositAddr := common.HexToAddress(depositPrecompileAddress)
-	assetAddr, err := assetToBytes(defaultAssetID)
-	if err != nil {
-		return err
-	}
-	// assetAddr := common.HexToAddress(defaultAssetID)
-	stakerAddr := common.HexToAddress(stakerAddress)
-	opAmount := amount
-
-	_, ethClient, err := connectToEthereum(rpcUrl)
-	if err != nil {
-		return err
-	}
-
-	sk, callAddr, err := getPrivateKeyAndAddress(privateKey)
+	depositAbi, data, err := depositLSTCall(layerZeroID, defaultAssetID, stakerAddress, amount)
 	if err != nil {
 		return err
 	}
+	return executeTx(rpcUrl, "Deposit", common.HexToAddress(depositPrecompileAddress), depositAbi, data)
+}
 
-	chainID, err := ethClient.ChainID(context.Background())
+// depositLSTCall packs the depositLST calldata, shared by deposit and bulk deposit.
+func depositLSTCall(clientChainID uint32, assetID, stakerAddress string, amount *big.Int) (abi.ABI, []byte, error) {
+	assetAddr, err := assetToBytes(assetID)
 	if err != nil {
-		return err
+		return abi.ABI{}, nil, err
 	}
+	stakerAddr := common.HexToAddress(stakerAddress)
 
 	depositAbi, err := abi.JSON(strings.NewReader(DepositABI))
 	if err != nil {
-		return err
-	}
-
-	data, err := depositAbi.Pack("depositLST", layerZeroID, assetAddr, paddingAddressTo32(stakerAddr), opAmount)
-	if err != nil {
-		return err
+		return abi.ABI{}, nil, err
 	}
 
-	txID, err := sendTransaction(ethClient, chainID, callAddr, sk, depositAddr, data)
+	data, err := depositAbi.Pack("depositLST", clientChainID, assetAddr, paddingAddressTo32(stakerAddr), amount)
 	if err != nil {
-		return err
+		return abi.ABI{}, nil, err
 	}
-
-	fmt.Println("Deposit Transaction ID:", txID)
-	return waitForTransaction(ethClient, txID)
+	return depositAbi, data, nil
 }
 
 func delegateTo_(rpcUrl, stakerAddress, operatorBench32Str string, amount *big.Int) error {
-	delegateAddr := common.HexToAddress(delegatePrecompileAddress)
-	assetAddr, err := assetToBytes(defaultAssetID)
-	if err != nil {
-		return err
-	}
-	// assetAddr := common.HexToAddress(defaultAssetID)
-	stakerAddr := common.HexToAddress(stakerAddress)
-	operatorAddr := []byte(operatorBench32Str)
-	opAmount := amount
-	_, ethClient, err := connectToEthereum(rpcUrl)
+	delegateAbi, data, err := delegateCall(layerZeroID, defaultAssetID, stakerAddress, operatorBench32Str, amount)
 	if err != nil {
 		return err
 	}
+	return executeTx(rpcUrl, "Delegate To", common.HexToAddress(delegatePrecompileAddress), delegateAbi, data)
+}
 
-	sk, callAddr, err := getPrivateKeyAndAddress(privateKey)
+// delegateCall packs the delegate calldata, shared by delegate and bulk delegate.
+func delegateCall(clientChainID uint32, assetID, stakerAddress, operatorBench32Str string, amount *big.Int) (abi.ABI, []byte, error) {
+	assetAddr, err := assetToBytes(assetID)
 	if err != nil {
-		return err
+		return abi.ABI{}, nil, err
 	}
-
-	chainID, err := ethClient.ChainID(context.Background())
+	stakerAddr := common.HexToAddress(stakerAddress)
+	operator, err := normalizeOperator(operatorBench32Str)
 	if err != nil {
-		return err
+		return abi.ABI{}, nil, err
 	}
 
 	delegateAbi, err := abi.JSON(strings.NewReader(DelegateABI))
 	if err != nil {
-		return err
-	}
-
-	data, err := delegateAbi.Pack("delegate", layerZeroID, assetAddr, paddingAddressTo32(stakerAddr), operatorAddr, opAmount)
-	if err != nil {
-		return err
+		return abi.ABI{}, nil, err
 	}
 
-	txID, err := sendTransaction(ethClient, chainID, callAddr, sk, delegateAddr, data)
+	data, err := delegateAbi.Pack("delegate", clientChainID, assetAddr, paddingAddressTo32(stakerAddr), []byte(operator), amount)
 	if err != nil {
-		return err
+		return abi.ABI{}, nil, err
 	}
-
-	fmt.Println("Delegate To Transaction ID:", txID)
-	return waitForTransaction(ethClient, txID)
+	return delegateAbi, data, nil
 }
 
 func undelegate_(rpcUrl, stakerAddress, operatorBench32Str string, amount *big.Int, instantUnbond bool) error {
-	delegateAddr := common.HexToAddress(delegatePrecompileAddress)
-	assetAddr, err := assetToBytes(defaultAssetID)
-	if err != nil {
-		return err
-	}
-	// assetAddr := common.HexToAddress(defaultAssetID)
-	stakerAddr := common.HexToAddress(stakerAddress)
-	operatorAddr := []byte(operatorBench32Str)
-	opAmount := amount
-
-	_, ethClient, err := connectToEthereum(rpcUrl)
+	delegateAbi, data, err := undelegateCall(layerZeroID, defaultAssetID, stakerAddress, operatorBench32Str, amount, instantUnbond)
 	if err != nil {
 		return err
 	}
+	return executeTx(rpcUrl, "Undelegate", common.HexToAddress(delegatePrecompileAddress), delegateAbi, data)
+}
 
-	sk, callAddr, err := getPrivateKeyAndAddress(privateKey)
+// undelegateCall packs the undelegate calldata, shared by undelegate and bulk undelegate.
+func undelegateCall(clientChainID uint32, assetID, stakerAddress, operatorBench32Str string, amount *big.Int, instantUnbond bool) (abi.ABI, []byte, error) {
+	assetAddr, err := assetToBytes(assetID)
 	if err != nil {
-		return err
+		return abi.ABI{}, nil, err
 	}
-
-	chainID, err := ethClient.ChainID(context.Background())
+	stakerAddr := common.HexToAddress(stakerAddress)
+	operator, err := normalizeOperator(operatorBench32Str)
 	if err != nil {
-		return err
+		return abi.ABI{}, nil, err
 	}
 
 	delegateAbi, err := abi.JSON(strings.NewReader(DelegateABI))
 	if err != nil {
-		return err
-	}
-
-	data, err := delegateAbi.Pack("undelegate", layerZeroID, assetAddr, paddingAddressTo32(stakerAddr), operatorAddr, opAmount, instantUnbond)
-	if err != nil {
-		return err
+		return abi.ABI{}, nil, err
 	}
 
-	txID, err := sendTransaction(ethClient, chainID, callAddr, sk, delegateAddr, data)
+	data, err := delegateAbi.Pack("undelegate", clientChainID, assetAddr, paddingAddressTo32(stakerAddr), []byte(operator), amount, instantUnbond)
 	if err != nil {
-		return err
+		return abi.ABI{}, nil, err
 	}
-
-	fmt.Println("Undelegate Transaction ID:", txID)
-	return waitForTransaction(ethClient, txID)
+	return delegateAbi, data, nil
 }
 
 func selfDelegate_(rpcUrl, stakerAddr, operatorBench32Str string) error {
 	delegateAddr := common.HexToAddress(delegatePrecompileAddress)
-	operatorAddr := []byte(operatorBench32Str)
-
-	_, ethClient, err := connectToEthereum(rpcUrl)
-	if err != nil {
-		return err
-	}
-
-	sk, callAddr, err := getPrivateKeyAndAddress(privateKey)
-	if err != nil {
-		return err
-	}
-
-	chainID, err := ethClient.ChainID(context.Background())
+	operator, err := normalizeOperator(operatorBench32Str)
 	if err != nil {
 		return err
 	}
+	operatorAddr := []byte(operator)
 
 	delegateAbi, err := abi.JSON(strings.NewReader(DelegateABI))
 	if err != nil {
 		return err
 	}
 
-	staker, err := hex.DecodeString(stakerAddr)
+	staker, err := hexutil.Decode(stakerAddr)
 	if err != nil {
-		return err
+		return fmt.Errorf("invalid staker address %q: %v", stakerAddr, err)
 	}
-
-	data, err := delegateAbi.Pack("associateOperatorWithStaker", layerZeroID, staker, operatorAddr)
-	if err != nil {
-		return err
+	if len(staker) != common.AddressLength {
+		return fmt.Errorf("invalid staker address %q: %d bytes, expected %d", stakerAddr, len(staker), common.AddressLength)
 	}
 
-	txID, err := sendTransaction(ethClient, chainID, callAddr, sk, delegateAddr, data)
+	data, err := delegateAbi.Pack("associateOperatorWithStaker", layerZeroID, staker, operatorAddr)
 	if err != nil {
 		return err
 	}
 
-	fmt.Println("Self Delegate Transaction ID:", txID)
-	return waitForTransaction(ethClient, txID)
+	return executeTx(rpcUrl, "Self Delegate", delegateAddr, delegateAbi, data)
 }
 
 func cancelSelfDelegate_(rpcUrl, stakerAddr string) error {
 	delegateAddr := common.HexToAddress(delegatePrecompileAddress)
 
-	_, ethClient, err := connectToEthereum(rpcUrl)
-	if err != nil {
-		return err
-	}
-
-	sk, callAddr, err := getPrivateKeyAndAddress(privateKey)
-	if err != nil {
-		return err
-	}
-
-	chainID, err := ethClient.ChainID(context.Background())
-	if err != nil {
-		return err
-	}
-
 	delegateAbi, err := abi.JSON(strings.NewReader(DelegateABI))
 	if err != nil {
 		return err
 	}
 
-	staker, err := hex.DecodeString(stakerAddr)
+	staker, err := hexutil.Decode(stakerAddr)
 	if err != nil {
-		return err
+		return fmt.Errorf("invalid staker address %q: %v", stakerAddr, err)
 	}
-
-	data, err := delegateAbi.Pack("dissociateOperatorFromStaker", layerZeroID, staker)
-	if err != nil {
-		return err
+	if len(staker) != common.AddressLength {
+		return fmt.Errorf("invalid staker address %q: %d bytes, expected %d", stakerAddr, len(staker), common.AddressLength)
 	}
 
-	txID, err := sendTransaction(ethClient, chainID, callAddr, sk, delegateAddr, data)
+	data, err := delegateAbi.Pack("dissociateOperatorFromStaker", layerZeroID, staker)
 	if err != nil {
 		return err
 	}
 
-	fmt.Println("Cancel Self Delegate Transaction ID:", txID)
-	return waitForTransaction(ethClient, txID)
+	return executeTx(rpcUrl, "Cancel Self Delegate", delegateAddr, delegateAbi, data)
 }
 
 func withdrawLST_(rpcUrl, stakerAddress string, amount *big.Int) error {
-	depositAddr := common.HexToAddress(depositPrecompileAddress)
-	assetAddr, err := assetToBytes(defaultAssetID)
-	if err != nil {
-		return err
-	}
-	// assetAddr := common.HexToAddress(defaultAssetID)
-	stakerAddr := common.HexToAddress(stakerAddress)
-	opAmount := amount
-
-	_, ethClient, err := connectToEthereum(rpcUrl)
-	if err != nil {
-		return err
-	}
-
-	sk, callAddr, err := getPrivateKeyAndAddress(privateKey)
+	depositAbi, data, err := withdrawLSTCall(layerZeroID, defaultAssetID, stakerAddress, amount)
 	if err != nil {
 		return err
 	}
+	return executeTx(rpcUrl, "Withdraw LST", common.HexToAddress(depositPrecompileAddress), depositAbi, data)
+}
 
-	chainID, err := ethClient.ChainID(context.Background())
+// withdrawLSTCall packs the withdrawLST calldata, shared by withdraw and bulk withdraw.
+func withdrawLSTCall(clientChainID uint32, assetID, stakerAddress string, amount *big.Int) (abi.ABI, []byte, error) {
+	assetAddr, err := assetToBytes(assetID)
 	if err != nil {
-		return err
+		return abi.ABI{}, nil, err
 	}
+	stakerAddr := common.HexToAddress(stakerAddress)
 
 	depositAbi, err := abi.JSON(strings.NewReader(DepositABI))
 	if err != nil {
-		return err
-	}
-
-	data, err := depositAbi.Pack("withdrawLST", layerZeroID, assetAddr, paddingAddressTo32(stakerAddr), opAmount)
-	if err != nil {
-		return err
+		return abi.ABI{}, nil, err
 	}
 
-	txID, err := sendTransaction(ethClient, chainID, callAddr, sk, depositAddr, data)
+	data, err := depositAbi.Pack("withdrawLST", clientChainID, assetAddr, paddingAddressTo32(stakerAddr), amount)
 	if err != nil {
-		return err
+		return abi.ABI{}, nil, err
 	}
-
-	fmt.Println("Withdraw LST Transaction ID:", txID)
-	return waitForTransaction(ethClient, txID)
+	return depositAbi, data, nil
 }
 
 func depositNST_(rpcUrl, pubkey string, stakerAddress string, amount *big.Int) error {
 	depositAddr := common.HexToAddress(depositPrecompileAddress)
 	stakerAddr := common.HexToAddress(stakerAddress)
 	opAmount := amount
+	pubkey = strings.TrimPrefix(pubkey, "0x")
 	if len(pubkey) != 64 {
 		return fmt.Errorf("invalid pubkey length: %d", len(pubkey))
 	}
 	pubkeyBytes := common.Hex2Bytes(pubkey)
-	_, ethClient, err := connectToEthereum(rpcUrl)
-	if err != nil {
-		return err
-	}
-
-	sk, callAddr, err := getPrivateKeyAndAddress(privateKey)
-	if err != nil {
-		return err
-	}
-
-	chainID, err := ethClient.ChainID(context.Background())
-	if err != nil {
-		return err
-	}
 
 	depositAbi, err := abi.JSON(strings.NewReader(DepositABI))
 	if err != nil {
@@ -2069,37 +2046,18 @@ func depositNST_(rpcUrl, pubkey string, stakerAddress string, amount *big.Int) e
 		return err
 	}
 
-	txID, err := sendTransaction(ethClient, chainID, callAddr, sk, depositAddr, data)
-	if err != nil {
-		return err
-	}
-
-	fmt.Println("Deposit NST Transaction ID:", txID)
-	return waitForTransaction(ethClient, txID)
+	return executeTx(rpcUrl, "Deposit NST", depositAddr, depositAbi, data)
 }
 
 func withdrawNST_(rpcUrl, pubkey string, stakerAddress string, amount *big.Int) error {
 	depositAddr := common.HexToAddress(depositPrecompileAddress)
 	stakerAddr := common.HexToAddress(stakerAddress)
 	opAmount := amount
+	pubkey = strings.TrimPrefix(pubkey, "0x")
 	if len(pubkey) != 64 {
 		return fmt.Errorf("invalid pubkey length: %d", len(pubkey))
 	}
 	pubkeyBytes := common.Hex2Bytes(pubkey)
-	_, ethClient, err := connectToEthereum(rpcUrl)
-	if err != nil {
-		return err
-	}
-
-	sk, callAddr, err := getPrivateKeyAndAddress(privateKey)
-	if err != nil {
-		return err
-	}
-
-	chainID, err := ethClient.ChainID(context.Background())
-	if err != nil {
-		return err
-	}
 
 	depositAbi, err := abi.JSON(strings.NewReader(DepositABI))
 	if err != nil {
@@ -2111,16 +2069,10 @@ func withdrawNST_(rpcUrl, pubkey string, stakerAddress string, amount *big.Int)
 		return err
 	}
 
-	txID, err := sendTransaction(ethClient, chainID, callAddr, sk, depositAddr, data)
-	if err != nil {
-		return err
-	}
-
-	fmt.Println("Withdraw NST Transaction ID:", txID)
-	return waitForTransaction(ethClient, txID)
+	return executeTx(rpcUrl, "Withdraw NST", depositAddr, depositAbi, data)
 }
 
-func registerToken_(rpcUrl, assetAddress string, decimals uint8, name string, metaData string, oracleInfo string) error {
+func registerToken_(rpcUrl, assetAddress string, decimals uint8, name string, symbol string, metaData string, oracleInfo string) error {
 	depositAddr := common.HexToAddress(depositPrecompileAddress)
 	var token []byte
 	if len(assetAddress) == 42 {
@@ -2132,21 +2084,6 @@ func registerToken_(rpcUrl, assetAddress string, decimals uint8, name string, me
 		return fmt.Errorf("invalid asset address length: %d", len(assetAddress))
 	}
 
-	_, ethClient, err := connectToEthereum(rpcUrl)
-	if err != nil {
-		return err
-	}
-
-	sk, callAddr, err := getPrivateKeyAndAddress(privateKey)
-	if err != nil {
-		return err
-	}
-
-	chainID, err := ethClient.ChainID(context.Background())
-	if err != nil {
-		return err
-	}
-
 	depositAbi, err := abi.JSON(strings.NewReader(DepositABI))
 	if err != nil {
 		return err
@@ -2157,13 +2094,17 @@ func registerToken_(rpcUrl, assetAddress string, decimals uint8, name string, me
 		return err
 	}
 
-	txID, err := sendTransaction(ethClient, chainID, callAddr, sk, depositAddr, data)
+	err = executeTx(rpcUrl, "RegisterToken", depositAddr, depositAbi, data)
 	if err != nil {
 		return err
 	}
-
-	fmt.Println("RegisterToken Transaction ID:", txID)
-	return waitForTransaction(ethClient, txID)
+	// registerToken takes no symbol, so the registry entry needs --symbol
+	if symbol == "" {
+		fmt.Fprintln(textOut, "Not recording the token in the registry without --symbol, add it with registry add-asset")
+		return nil
+	}
+	recordAsset(registryAsset{Address: assetAddress, ChainID: layerZeroID, Symbol: symbol, Decimals: decimals, OracleInfo: oracleInfo})
+	return nil
 }
 
 func updateToken_(rpcUrl, assetAddress string, metaData string) error {
@@ -2171,21 +2112,6 @@ func updateToken_(rpcUrl, assetAddress string, metaData string) error {
 	assetAddr := common.HexToAddress(assetAddress)
 	token := paddingAddressTo32(assetAddr)
 
-	_, ethClient, err := connectToEthereum(rpcUrl)
-	if err != nil {
-		return err
-	}
-
-	sk, callAddr, err := getPrivateKeyAndAddress(privateKey)
-	if err != nil {
-		return err
-	}
-
-	chainID, err := ethClient.ChainID(context.Background())
-	if err != nil {
-		return err
-	}
-
 	depositAbi, err := abi.JSON(strings.NewReader(DepositABI))
 	if err != nil {
 		return err
@@ -2196,33 +2122,12 @@ func updateToken_(rpcUrl, assetAddress string, metaData string) error {
 		return err
 	}
 
-	txID, err := sendTransaction(ethClient, chainID, callAddr, sk, depositAddr, data)
-	if err != nil {
-		return err
-	}
-
-	fmt.Println("updateToken Transaction ID:", txID)
-	return waitForTransaction(ethClient, txID)
+	return executeTx(rpcUrl, "updateToken", depositAddr, depositAbi, data)
 }
 
 func registerOrUpdateClientChain_(rpcUrl string, clientChainID uint32, addressLength uint8, name string, metaInfo string, signatureType string) error {
 	depositAddr := common.HexToAddress(depositPrecompileAddress)
 
-	_, ethClient, err := connectToEthereum(rpcUrl)
-	if err != nil {
-		return err
-	}
-
-	sk, callAddr, err := getPrivateKeyAndAddress(privateKey)
-	if err != nil {
-		return err
-	}
-
-	chainID, err := ethClient.ChainID(context.Background())
-	if err != nil {
-		return err
-	}
-
 	depositAbi, err := abi.JSON(strings.NewReader(DepositABI))
 	if err != nil {
 		return err
@@ -2233,30 +2138,47 @@ func registerOrUpdateClientChain_(rpcUrl string, clientChainID uint32, addressLe
 		return err
 	}
 
-	txID, err := sendTransaction(ethClient, chainID, callAddr, sk, depositAddr, data)
+	// --offline and --build-only never talk to a node, so they cannot tell which one it will be
+	if !offline && !buildOnly {
+		registered, err := isRegisteredClientChain_(rpcUrl, clientChainID)
+		if err != nil {
+			return fmt.Errorf("failed to check if client chain %d is registered: %v", clientChainID, err)
+		}
+		if registered {
+			fmt.Fprintf(textOut, "Client chain %d is already registered, updating it\n", clientChainID)
+		} else {
+			fmt.Fprintf(textOut, "Client chain %d is not registered yet, registering it\n", clientChainID)
+		}
+	}
+
+	err = executeTx(rpcUrl, "registerOrUpdateClientChain", depositAddr, depositAbi, data)
 	if err != nil {
 		return err
 	}
-
-	fmt.Println("registerOrUpdateClientChain Transaction ID:", txID)
-	return waitForTransaction(ethClient, txID)
+	recordChain(registryChain{ID: clientChainID, Name: name, AddressLength: addressLength, SignatureType: signatureType})
+	return nil
 }
 
 func claimReward_(rpcUrl string, clientChainID uint32, stakerAddress string) error {
 	rewardAddr := common.HexToAddress(rewardPrecompileAddress)
 	stakerAddr := common.HexToAddress(stakerAddress)
 
-	_, ethClient, err := connectToEthereum(rpcUrl)
+	rewardAbi, err := abi.JSON(strings.NewReader(rewardABI))
 	if err != nil {
 		return err
 	}
 
-	sk, callAddr, err := getPrivateKeyAndAddress(privateKey)
+	data, err := rewardAbi.Pack("claimReward", clientChainID, paddingAddressTo32(stakerAddr))
 	if err != nil {
 		return err
 	}
+	return executeTx(rpcUrl, "Claim Reward", rewardAddr, rewardAbi, data)
+}
 
-	chainID, err := ethClient.ChainID(context.Background())
+func fundAVSReward_(rpcUrl string, rewardAssetChainID uint32, avsAddress string, assetAddress string, amount *big.Int) error {
+	rewardAddr := common.HexToAddress(rewardPrecompileAddress)
+	avsAddr := common.HexToAddress(avsAddress)
+	assetAddr, err := assetToBytes(assetAddress)
 	if err != nil {
 		return err
 	}
@@ -2266,59 +2188,12 @@ func claimReward_(rpcUrl string, clientChainID uint32, stakerAddress string) err
 		return err
 	}
 
-	data, err := rewardAbi.Pack("claimReward", clientChainID, paddingAddressTo32(stakerAddr))
-	if err != nil {
-		return err
-	}
-	txID, err := sendTransaction(ethClient, chainID, callAddr, sk, rewardAddr, data)
-	if err != nil {
-		return err
-	}
-
-	fmt.Println("Claim Reward Transaction ID:", txID)
-	return waitForTransaction(ethClient, txID)
-}
-
-func fundAVSReward_(rpcUrl string, rewardAssetChainID uint32, avsAddress string, assetAddress string, amount *big.Int) error {
-	rewardAddr := common.HexToAddress(rewardPrecompileAddress)
-	avsAddr := common.HexToAddress(avsAddress)
-	assetAddr, err := assetToBytes(assetAddress)
-	if err != nil {
-		return err
-	}
-
-	_, ethClient, err := connectToEthereum(rpcUrl)
-	if err != nil {
-		return err
-	}
-
-	sk, callAddr, err := getPrivateKeyAndAddress(privateKey)
+	data, err := rewardAbi.Pack("fundAVSReward", rewardAssetChainID, paddingAddressTo32(avsAddr), assetAddr, &amount)
 	if err != nil {
 		return err
 	}
 
-	chainID, err := ethClient.ChainID(context.Background())
-	if err != nil {
-		return err
-	}
-
-	rewardAbi, err := abi.JSON(strings.NewReader(rewardABI))
-	if err != nil {
-		return err
-	}
-
-	data, err := rewardAbi.Pack("fundAVSReward", rewardAssetChainID, paddingAddressTo32(avsAddr), assetAddr, &amount)
-	if err != nil {
-		return err
-	}
-
-	txID, err := sendTransaction(ethClient, chainID, callAddr, sk, rewardAddr, data)
-	if err != nil {
-		return err
-	}
-
-	fmt.Println("Fund AVS Reward Transaction ID:", txID)
-	return waitForTransaction(ethClient, txID)
+	return executeTx(rpcUrl, "Fund AVS Reward", rewardAddr, rewardAbi, data)
 }
 
 func isRegisteredRewardToken_(rpcUrl string, clientChainID uint32, tokenAddress string) (bool, error) {
@@ -2369,21 +2244,6 @@ func registerRewardToken_(rpcUrl string, clientChainID uint32, tokenAddress stri
 		return err
 	}
 
-	_, ethClient, err := connectToEthereum(rpcUrl)
-	if err != nil {
-		return err
-	}
-
-	sk, callAddr, err := getPrivateKeyAndAddress(privateKey)
-	if err != nil {
-		return err
-	}
-
-	chainID, err := ethClient.ChainID(context.Background())
-	if err != nil {
-		return err
-	}
-
 	rewardAbi, err := abi.JSON(strings.NewReader(rewardABI))
 	if err != nil {
 		return err
@@ -2394,33 +2254,17 @@ func registerRewardToken_(rpcUrl string, clientChainID uint32, tokenAddress stri
 		return err
 	}
 
-	txID, err := sendTransaction(ethClient, chainID, callAddr, sk, rewardAddr, data)
+	err = executeTx(rpcUrl, "Register Reward Token", rewardAddr, rewardAbi, data)
 	if err != nil {
 		return err
 	}
-
-	fmt.Println("Register Reward Token Transaction ID:", txID)
-	return waitForTransaction(ethClient, txID)
+	recordAsset(registryAsset{Address: tokenAddress, ChainID: clientChainID, Symbol: symbol, Decimals: decimals})
+	return nil
 }
 
 func setAVSEpochReward_(rpcUrl string, denomination string, amount *big.Int) error {
 	rewardAddr := common.HexToAddress(rewardPrecompileAddress)
 
-	_, ethClient, err := connectToEthereum(rpcUrl)
-	if err != nil {
-		return err
-	}
-
-	sk, callAddr, err := getPrivateKeyAndAddress(privateKey)
-	if err != nil {
-		return err
-	}
-
-	chainID, err := ethClient.ChainID(context.Background())
-	if err != nil {
-		return err
-	}
-
 	rewardAbi, err := abi.JSON(strings.NewReader(rewardABI))
 	if err != nil {
 		return err
@@ -2443,33 +2287,12 @@ func setAVSEpochReward_(rpcUrl string, denomination string, amount *big.Int) err
 		return err
 	}
 
-	txID, err := sendTransaction(ethClient, chainID, callAddr, sk, rewardAddr, data)
-	if err != nil {
-		return err
-	}
-
-	fmt.Println("Set AVS Epoch Reward Transaction ID:", txID)
-	return waitForTransaction(ethClient, txID)
+	return executeTx(rpcUrl, "Set AVS Epoch Reward", rewardAddr, rewardAbi, data)
 }
 
 func setAVSRewardParams_(rpcUrl string, isCustomRewardInflation bool, isCustomOperatorRatio bool) error {
 	rewardAddr := common.HexToAddress(rewardPrecompileAddress)
 
-	_, ethClient, err := connectToEthereum(rpcUrl)
-	if err != nil {
-		return err
-	}
-
-	sk, callAddr, err := getPrivateKeyAndAddress(privateKey)
-	if err != nil {
-		return err
-	}
-
-	chainID, err := ethClient.ChainID(context.Background())
-	if err != nil {
-		return err
-	}
-
 	rewardAbi, err := abi.JSON(strings.NewReader(rewardABI))
 	if err != nil {
 		return err
@@ -2480,29 +2303,12 @@ func setAVSRewardParams_(rpcUrl string, isCustomRewardInflation bool, isCustomOp
 		return err
 	}
 
-	txID, err := sendTransaction(ethClient, chainID, callAddr, sk, rewardAddr, data)
-	if err != nil {
-		return err
-	}
-
-	fmt.Println("Set AVS Reward Params Transaction ID:", txID)
-	return waitForTransaction(ethClient, txID)
+	return executeTx(rpcUrl, "Set AVS Reward Params", rewardAddr, rewardAbi, data)
 }
 
 func setOperatorRewardProportions_(rpcUrl string, operator string, numerator *big.Int, denominator *big.Int) error {
 	rewardAddr := common.HexToAddress(rewardPrecompileAddress)
-
-	_, ethClient, err := connectToEthereum(rpcUrl)
-	if err != nil {
-		return err
-	}
-
-	sk, callAddr, err := getPrivateKeyAndAddress(privateKey)
-	if err != nil {
-		return err
-	}
-
-	chainID, err := ethClient.ChainID(context.Background())
+	operator, err := normalizeOperator(operator)
 	if err != nil {
 		return err
 	}
@@ -2531,32 +2337,18 @@ func setOperatorRewardProportions_(rpcUrl string, operator string, numerator *bi
 		return err
 	}
 
-	txID, err := sendTransaction(ethClient, chainID, callAddr, sk, rewardAddr, data)
-	if err != nil {
-		return err
-	}
-
-	fmt.Println("Set Operator Reward Proportions Transaction ID:", txID)
-	return waitForTransaction(ethClient, txID)
+	return executeTx(rpcUrl, "Set Operator Reward Proportions", rewardAddr, rewardAbi, data)
 }
 
 func setStakerRewardParams_(rpcUrl string, clientChainID uint32, stakerAddress string, redelegateReward bool, redelegateOperator string) error {
 	rewardAddr := common.HexToAddress(rewardPrecompileAddress)
 	stakerAddr := common.HexToAddress(stakerAddress)
-
-	_, ethClient, err := connectToEthereum(rpcUrl)
-	if err != nil {
-		return err
-	}
-
-	sk, callAddr, err := getPrivateKeyAndAddress(privateKey)
-	if err != nil {
-		return err
-	}
-
-	chainID, err := ethClient.ChainID(context.Background())
-	if err != nil {
-		return err
+	if redelegateOperator != "" {
+		operator, err := normalizeOperator(redelegateOperator)
+		if err != nil {
+			return err
+		}
+		redelegateOperator = operator
 	}
 
 	rewardAbi, err := abi.JSON(strings.NewReader(rewardABI))
@@ -2569,35 +2361,17 @@ func setStakerRewardParams_(rpcUrl string, clientChainID uint32, stakerAddress s
 		return err
 	}
 
-	txID, err := sendTransaction(ethClient, chainID, callAddr, sk, rewardAddr, data)
-	if err != nil {
-		return err
-	}
-
-	fmt.Println("Set Staker Reward Params Transaction ID:", txID)
-	return waitForTransaction(ethClient, txID)
+	return executeTx(rpcUrl, "Set Staker Reward Params", rewardAddr, rewardAbi, data)
 }
 
 func undelegateReward_(rpcUrl string, clientChainID uint32, rewardAssetChainID uint32, stakerAddress string, operatorBench32Str string, amount *big.Int, instantUnbond bool) error {
 	rewardAddr := common.HexToAddress(rewardPrecompileAddress)
 	stakerAddr := common.HexToAddress(stakerAddress)
-	operatorAddr := operatorBench32Str
-	assetAddr, err := assetToBytes(defaultAssetID)
-	if err != nil {
-		return err
-	}
-
-	_, ethClient, err := connectToEthereum(rpcUrl)
-	if err != nil {
-		return err
-	}
-
-	sk, callAddr, err := getPrivateKeyAndAddress(privateKey)
+	operatorAddr, err := normalizeOperator(operatorBench32Str)
 	if err != nil {
 		return err
 	}
-
-	chainID, err := ethClient.ChainID(context.Background())
+	assetAddr, err := assetToBytes(defaultAssetID)
 	if err != nil {
 		return err
 	}
@@ -2610,33 +2384,33 @@ func undelegateReward_(rpcUrl string, clientChainID uint32, rewardAssetChainID u
 	// Create tuple for parameters
 	type tuple struct {
 		ClientChainLzID      uint32
-		RewardAssetChainLzID   uint32
-		AssetAddr             []byte
-		StakerAddr            [32]byte
-		OperatorAddr          string
-		OpAmount              *big.Int
-		InstantUnbond         bool
+		RewardAssetChainLzID uint32
+		AssetAddr            []byte
+		StakerAddr           [32]byte
+		OperatorAddr         string
+		OpAmount             *big.Int
+		InstantUnbond        bool
 	}
 
 	// Include clientChainLzID in the parameters
 	stakerAddrBytes := paddingAddressTo32(stakerAddr)
 
 	params := struct {
-		ClientChainLzID uint32
+		ClientChainLzID      uint32
 		RewardAssetChainLzID uint32
-		AssetAddress []byte
-		StakerAddress []byte
-		OperatorAddr string
-		OpAmount *big.Int
-		InstantUnbond bool
+		AssetAddress         []byte
+		StakerAddress        []byte
+		OperatorAddr         string
+		OpAmount             *big.Int
+		InstantUnbond        bool
 	}{
-		ClientChainLzID: clientChainID,
+		ClientChainLzID:      clientChainID,
 		RewardAssetChainLzID: rewardAssetChainID,
-		AssetAddress: assetAddr,
-		StakerAddress: stakerAddrBytes,
-		OperatorAddr: operatorAddr,
-		OpAmount: amount,
-		InstantUnbond: instantUnbond,
+		AssetAddress:         assetAddr,
+		StakerAddress:        stakerAddrBytes,
+		OperatorAddr:         operatorAddr,
+		OpAmount:             amount,
+		InstantUnbond:        instantUnbond,
 	}
 
 	data, err := rewardAbi.Pack("undelegateReward", params)
@@ -2644,13 +2418,7 @@ func undelegateReward_(rpcUrl string, clientChainID uint32, rewardAssetChainID u
 		return err
 	}
 
-	txID, err := sendTransaction(ethClient, chainID, callAddr, sk, rewardAddr, data)
-	if err != nil {
-		return err
-	}
-
-	fmt.Println("Undelegate Reward Transaction ID:", txID)
-	return waitForTransaction(ethClient, txID)
+	return executeTx(rpcUrl, "Undelegate Reward", rewardAddr, rewardAbi, data)
 }
 
 func updateRewardToken_(rpcUrl string, clientChainID uint32, tokenAddress string, metaData string) error {
@@ -2660,21 +2428,6 @@ func updateRewardToken_(rpcUrl string, clientChainID uint32, tokenAddress string
 		return err
 	}
 
-	_, ethClient, err := connectToEthereum(rpcUrl)
-	if err != nil {
-		return err
-	}
-
-	sk, callAddr, err := getPrivateKeyAndAddress(privateKey)
-	if err != nil {
-		return err
-	}
-
-	chainID, err := ethClient.ChainID(context.Background())
-	if err != nil {
-		return err
-	}
-
 	rewardAbi, err := abi.JSON(strings.NewReader(rewardABI))
 	if err != nil {
 		return err
@@ -2685,34 +2438,17 @@ func updateRewardToken_(rpcUrl string, clientChainID uint32, tokenAddress string
 		return err
 	}
 
-	txID, err := sendTransaction(ethClient, chainID, callAddr, sk, rewardAddr, data)
-	if err != nil {
-		return err
-	}
-
-	fmt.Println("Update Reward Token Transaction ID:", txID)
-	return waitForTransaction(ethClient, txID)
+	return executeTx(rpcUrl, "Update Reward Token", rewardAddr, rewardAbi, data)
 }
 
 func withdrawCommission_(rpcUrl string, rewardAssetChainID uint32, operatorBench32Str string, amount *big.Int) error {
 	rewardAddr := common.HexToAddress(rewardPrecompileAddress)
-	operatorAddr := []byte(operatorBench32Str)
-	assetAddr, err := assetToBytes(defaultAssetID)
-	if err != nil {
-		return err
-	}
-
-	_, ethClient, err := connectToEthereum(rpcUrl)
+	operator, err := normalizeOperator(operatorBench32Str)
 	if err != nil {
 		return err
 	}
-
-	sk, callAddr, err := getPrivateKeyAndAddress(privateKey)
-	if err != nil {
-		return err
-	}
-
-	chainID, err := ethClient.ChainID(context.Background())
+	operatorAddr := []byte(operator)
+	assetAddr, err := assetToBytes(defaultAssetID)
 	if err != nil {
 		return err
 	}
@@ -2727,34 +2463,17 @@ func withdrawCommission_(rpcUrl string, rewardAssetChainID uint32, operatorBench
 		return err
 	}
 
-	txID, err := sendTransaction(ethClient, chainID, callAddr, sk, rewardAddr, data)
-	if err != nil {
-		return err
-	}
-
-	fmt.Println("Withdraw Commission Transaction ID:", txID)
-	return waitForTransaction(ethClient, txID)
+	return executeTx(rpcUrl, "Withdraw Commission", rewardAddr, rewardAbi, data)
 }
 
 func withdrawIMUATokenCommission_(rpcUrl string, operatorBench32Str string, receiptAddress string, amount *big.Int) error {
 	rewardAddr := common.HexToAddress(rewardPrecompileAddress)
-	operatorAddr := []byte(operatorBench32Str)
-	receiptAddr := []byte(receiptAddress)
-
-	_, ethClient, err := connectToEthereum(rpcUrl)
-	if err != nil {
-		return err
-	}
-
-	sk, callAddr, err := getPrivateKeyAndAddress(privateKey)
-	if err != nil {
-		return err
-	}
-
-	chainID, err := ethClient.ChainID(context.Background())
+	operator, err := normalizeOperator(operatorBench32Str)
 	if err != nil {
 		return err
 	}
+	operatorAddr := []byte(operator)
+	receiptAddr := []byte(receiptAddress)
 
 	rewardAbi, err := abi.JSON(strings.NewReader(rewardABI))
 	if err != nil {
@@ -2766,34 +2485,13 @@ func withdrawIMUATokenCommission_(rpcUrl string, operatorBench32Str string, rece
 		return err
 	}
 
-	txID, err := sendTransaction(ethClient, chainID, callAddr, sk, rewardAddr, data)
-	if err != nil {
-		return err
-	}
-
-	fmt.Println("Withdraw IMUA Token Commission Transaction ID:", txID)
-	return waitForTransaction(ethClient, txID)
+	return executeTx(rpcUrl, "Withdraw IMUA Token Commission", rewardAddr, rewardAbi, data)
 }
 
 func withdrawIMUATokenReward_(rpcUrl string, clientChainID uint32, stakerAddress string, receiptAddress string, amount *big.Int) error {
 	rewardAddr := common.HexToAddress(rewardPrecompileAddress)
 	stakerAddr := common.HexToAddress(stakerAddress)
 
-	_, ethClient, err := connectToEthereum(rpcUrl)
-	if err != nil {
-		return err
-	}
-
-	sk, callAddr, err := getPrivateKeyAndAddress(privateKey)
-	if err != nil {
-		return err
-	}
-
-	chainID, err := ethClient.ChainID(context.Background())
-	if err != nil {
-		return err
-	}
-
 	rewardAbi, err := abi.JSON(strings.NewReader(rewardABI))
 	if err != nil {
 		return err
@@ -2805,19 +2503,19 @@ func withdrawIMUATokenReward_(rpcUrl string, clientChainID uint32, stakerAddress
 	// convert the receipt address to bytes, receiptAddress remove 0x prefix
 	receiptAddress = strings.TrimPrefix(receiptAddress, "0x")
 	receiptAddrBytes := common.Hex2Bytes(receiptAddress)
-	fmt.Println("receiptAddrBytes:", hexutil.Encode(receiptAddrBytes))
+	debugf("receiptAddrBytes: %s\n", hexutil.Encode(receiptAddrBytes))
 	params := struct {
-		DoClaim bool
+		DoClaim         bool
 		ClientChainLzID uint32
-		StakerAddress []byte
-		ReceiptAddress []byte
-		OpAmount *big.Int
+		StakerAddress   []byte
+		ReceiptAddress  []byte
+		OpAmount        *big.Int
 	}{
-		DoClaim: doClaim,
+		DoClaim:         doClaim,
 		ClientChainLzID: clientChainID,
-		StakerAddress: stakerAddrBytes,
-		ReceiptAddress: receiptAddrBytes,
-		OpAmount: amount,
+		StakerAddress:   stakerAddrBytes,
+		ReceiptAddress:  receiptAddrBytes,
+		OpAmount:        amount,
 	}
 	// pack the params into struct
 	data, err := rewardAbi.Pack("withdrawIMUATokenReward", params)
@@ -2825,13 +2523,7 @@ func withdrawIMUATokenReward_(rpcUrl string, clientChainID uint32, stakerAddress
 		return err
 	}
 
-	txID, err := sendTransaction(ethClient, chainID, callAddr, sk, rewardAddr, data)
-	if err != nil {
-		return err
-	}
-
-	fmt.Println("Withdraw IMUA Token Reward Transaction ID:", txID)
-	return waitForTransaction(ethClient, txID)
+	return executeTx(rpcUrl, "Withdraw IMUA Token Reward", rewardAddr, rewardAbi, data)
 }
 
 func withdrawReward_(rpcUrl string, clientChainID uint32, rewardAssetChainID uint32, stakerAddress string, amount *big.Int) error {
@@ -2842,21 +2534,6 @@ func withdrawReward_(rpcUrl string, clientChainID uint32, rewardAssetChainID uin
 		return err
 	}
 
-	_, ethClient, err := connectToEthereum(rpcUrl)
-	if err != nil {
-		return err
-	}
-
-	sk, callAddr, err := getPrivateKeyAndAddress(privateKey)
-	if err != nil {
-		return err
-	}
-
-	chainID, err := ethClient.ChainID(context.Background())
-	if err != nil {
-		return err
-	}
-
 	rewardAbi, err := abi.JSON(strings.NewReader(rewardABI))
 	if err != nil {
 		return err
@@ -2866,19 +2543,19 @@ func withdrawReward_(rpcUrl string, clientChainID uint32, rewardAssetChainID uin
 	stakerAddrBytes := paddingAddressTo32(stakerAddr)
 
 	params := struct {
-		DoClaim bool
-		ClientChainLzID uint32
+		DoClaim              bool
+		ClientChainLzID      uint32
 		RewardAssetChainLzID uint32
-		AssetAddress []byte
-		StakerAddress []byte
-		OpAmount *big.Int
+		AssetAddress         []byte
+		StakerAddress        []byte
+		OpAmount             *big.Int
 	}{
-		DoClaim: doClaim,
-		ClientChainLzID: clientChainID,
+		DoClaim:              doClaim,
+		ClientChainLzID:      clientChainID,
 		RewardAssetChainLzID: rewardAssetChainID,
-		AssetAddress: assetAddr,
-		StakerAddress: stakerAddrBytes,
-		OpAmount: amount,
+		AssetAddress:         assetAddr,
+		StakerAddress:        stakerAddrBytes,
+		OpAmount:             amount,
 	}
 
 	data, err := rewardAbi.Pack("withdrawReward", params)
@@ -2886,33 +2563,31 @@ func withdrawReward_(rpcUrl string, clientChainID uint32, rewardAssetChainID uin
 		return err
 	}
 
-	txID, err := sendTransaction(ethClient, chainID, callAddr, sk, rewardAddr, data)
-	if err != nil {
-		return err
-	}
-
-	fmt.Println("Withdraw Reward Transaction ID:", txID)
-	return waitForTransaction(ethClient, txID)
+	return executeTx(rpcUrl, "Withdraw Reward", rewardAddr, rewardAbi, data)
 }
 
+// rpcClients holds one connection per node URL, shared by all steps of a plan.
+var (
+	rpcClientsMu sync.Mutex
+	rpcClients   = make(map[string]*rpc.Client)
+)
+
 func connectToEthereum(nodeURL string) (*rpc.Client, *ethclient.Client, error) {
-	client, err := rpc.DialContext(context.Background(), nodeURL)
-	if err != nil {
-		return nil, nil, err
+	rpcClientsMu.Lock()
+	defer rpcClientsMu.Unlock()
+	client, ok := rpcClients[nodeURL]
+	if !ok {
+		var err error
+		client, err = rpc.DialContext(context.Background(), nodeURL)
+		if err != nil {
+			return nil, nil, err
+		}
+		rpcClients[nodeURL] = client
 	}
 	ethClient := ethclient.NewClient(client)
 	return client, ethClient, nil
 }
 
-func getPrivateKeyAndAddress(privateKey string) (*ecdsa.PrivateKey, common.Address, error) {
-	sk, err := crypto.HexToECDSA(privateKey)
-	if err != nil {
-		return nil, common.Address{}, err
-	}
-	callAddr := crypto.PubkeyToAddress(sk.PublicKey)
-	return sk, callAddr, nil
-}
-
 func paddingAddressTo32(address common.Address) []byte {
 	paddingLen := 32 - len(address)
 	ret := make([]byte, len(address))
@@ -2920,7 +2595,7 @@ func paddingAddressTo32(address common.Address) []byte {
 	for i := 0; i < paddingLen; i++ {
 		ret = append(ret, 0)
 	}
-	fmt.Println("Padded address:", hexutil.Encode(ret))
+	debugf("Padded address: %s\n", hexutil.Encode(ret))
 	return ret
 }
 
@@ -2934,80 +2609,198 @@ func assetToBytes(assetAddress string) ([]byte, error) {
 	return nil, fmt.Errorf("invalid asset address length: %d", len(assetAddress))
 }
 
-func sendTransaction(client *ethclient.Client, chainID *big.Int, from common.Address, sk *ecdsa.PrivateKey, to common.Address, data []byte) (string, error) {
-	ctx := context.Background()
-	nonce, err := client.NonceAt(ctx, from, nil)
+// stakerID builds the <address>_<lzID hex> staker ID used by exocored queries.
+func stakerID(address common.Address, clientChainID uint32) string {
+	return hexutil.Encode(address.Bytes()) + "_" + hexutil.EncodeUint64(uint64(clientChainID))
+}
+
+func addressToBech32(hrp string, address common.Address) (string, error) {
+	conv, err := bech32.ConvertBits(address.Bytes(), 8, 5, true)
 	if err != nil {
 		return "", err
 	}
+	return bech32.Encode(hrp, conv)
+}
 
-	gasPrice, err := client.SuggestGasPrice(ctx)
+// bech32ToAddress decodes a bech32 account address such as exo1... into its
+// 20 bytes, returning the human readable part alongside.
+func bech32ToAddress(address string) (string, common.Address, error) {
+	hrp, data, err := bech32.Decode(address)
 	if err != nil {
-		return "", err
+		return "", common.Address{}, err
+	}
+	conv, err := bech32.ConvertBits(data, 5, 8, false)
+	if err != nil {
+		return "", common.Address{}, err
+	}
+	if len(conv) != common.AddressLength {
+		return "", common.Address{}, fmt.Errorf("%s decodes to %d bytes, expected %d", address, len(conv), common.AddressLength)
+	}
+	return hrp, common.BytesToAddress(conv), nil
+}
+
+// executeTx simulates data against the precompile at to, then signs and sends
+// it, printing the tx ID under label. In --offline mode the signed tx is written
+// to a file instead, and --build-only emits the unsigned call without touching any key.
+func executeTx(rpcUrl, label string, to common.Address, contractAbi abi.ABI, data []byte) error {
+	recordCall(label, to, contractAbi, data)
+	if buildOnly {
+		if offline {
+			return fmt.Errorf("--build-only and --offline cannot be combined")
+		}
+		return buildUnsigned(rpcUrl, label, to, contractAbi, data)
+	}
+
+	signer, err := loadSigner()
+	if err != nil {
+		return withCode(errCodeSigner, err)
+	}
+	from := signer.Address()
+	report.From = &from
+	if offline {
+		return signOffline(signer, label, to, contractAbi, data)
+	}
+
+	_, ethClient, err := connectToEthereum(rpcUrl)
+	if err != nil {
+		return err
+	}
+
+	chainID, err := ethClient.ChainID(context.Background())
+	if err != nil {
+		return err
+	}
+
+	_, err = preflight(context.Background(), ethClient, signer.Address(), to, contractAbi, data)
+	if err != nil {
+		return err
+	}
+	if dryRun {
+		report.Status = "dry-run"
+		fmt.Fprintln(textOut, label, "dry run, transaction not sent")
+		return nil
+	}
+
+	txID, err := sendTransaction(ethClient, chainID, signer, to, data)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(textOut, label, "Transaction ID:", txID)
+	report.Status = "sent"
+	if noWait {
+		return nil
+	}
+	receipt, err := waitWithFeeBumps(ethClient, chainID, signer, txID)
+	if receipt != nil {
+		summary := summarizeReceipt(receipt)
+		report.Receipt = &summary
+		printReceipt(summary)
+	}
+	if err != nil {
+		return err
+	}
+	report.Status = "mined"
+
+	state, err := postTxState(context.Background(), ethClient, signer.Address(), to, contractAbi, data, receipt)
+	if err != nil {
+		return err
+	}
+	if state != nil {
+		report.Outputs = state.Outputs
+		report.OutputsBlock = state.Block
+		printPostTxState(state)
 	}
+	return nil
+}
 
-	gasLimit := uint64(500000)
-	tx := types.NewTx(&types.LegacyTx{
-		Nonce:    nonce,
-		To:       &to,
-		Value:    big.NewInt(0),
-		Gas:      gasLimit,
-		GasPrice: gasPrice,
-		Data:     data,
-	})
-	signer := types.LatestSignerForChainID(chainID)
-	signTx, err := types.SignTx(tx, signer, sk)
+// sendTransaction signs and sends data to to without waiting for it to be mined.
+// Nonces come from the account's nonce manager, so consecutive calls in one
+// process do not collide; a nonce rejected by the node is resynced and retried once.
+func sendTransaction(client *ethclient.Client, chainID *big.Int, signer Signer, to common.Address, data []byte) (string, error) {
+	ctx := context.Background()
+	from := signer.Address()
+
+	fees, err := resolveFees(ctx, client)
 	if err != nil {
 		return "", err
 	}
+	fmt.Fprintln(textOut, "Fees:", fees)
 
-	fmt.Println("the txID is:", signTx.Hash().String())
 	msg := ethereum.CallMsg{
 		From: from,
-		To:   tx.To(),
-		Data: tx.Data(),
+		To:   &to,
+		Data: data,
 	}
-	result, err := client.CallContract(context.Background(), msg, nil)
-	fmt.Println("The bool value returned by the contract:", result)
+	gasLimit, err := resolveGasLimit(ctx, client, msg)
 	if err != nil {
-		fmt.Println("Failed to call contract:", err)
+		return "", err
+	}
+	fmt.Fprintln(textOut, "Gas limit:", gasLimit)
+
+	if rootCmd.PersistentFlags().Changed("nonce") {
+		return signAndSend(ctx, client, chainID, signer, newTx(chainID, offlineNonce, to, gasLimit, fees, data))
+	}
+
+	nonces := nonceManagerFor(client, chainID, from)
+	for attempt := 0; ; attempt++ {
+		nonce, err := nonces.Next(ctx)
+		if err != nil {
+			return "", err
+		}
+		txID, err := signAndSend(ctx, client, chainID, signer, newTx(chainID, nonce, to, gasLimit, fees, data))
+		if err == nil {
+			return txID, nil
+		}
+		if attempt == 0 && isNonceError(err) {
+			fmt.Fprintf(textOut, "Nonce %d rejected (%v), resyncing with the node\n", nonce, err)
+			nonces.Resync()
+			continue
+		}
+		nonces.Release(nonce)
+		return "", err
 	}
-	if isZeroByteArray(result) {
-		fmt.Println("Failed to call contract,The bool value returned by the contract is false")
+}
+
+func signAndSend(ctx context.Context, client *ethclient.Client, chainID *big.Int, signer Signer, tx *types.Transaction) (string, error) {
+	signTx, err := signer.SignTx(tx, chainID)
+	if err != nil {
+		return "", err
 	}
+
+	debugf("the txID is: %s\n", signTx.Hash().String())
 	err = client.SendTransaction(ctx, signTx)
 	if err != nil {
 		return "", err
 	}
+	recordTx(signTx)
 	return signTx.Hash().String(), nil
 }
-func isZeroByteArray(byteArray []byte) bool {
-	for _, b := range byteArray {
-		if b != 0 {
-			return false
-		}
-	}
-	return true
-}
-func waitForTransaction(client *ethclient.Client, txID string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+
+// waitForTransaction waits until txID is mined with --confirmations and returns
+// its receipt. A mined but failed transaction returns both the receipt and an error.
+func waitForTransaction(client *ethclient.Client, txID string) (*types.Receipt, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), waitTimeout)
 	defer cancel()
 
 	txHash := common.HexToHash(txID)
 	tx, _, err := client.TransactionByHash(ctx, txHash)
 	if err != nil {
-		return fmt.Errorf("failed to get transaction: %v", err)
+		return nil, fmt.Errorf("failed to get transaction: %v", err)
 	}
 
 	receipt, err := bind.WaitMined(ctx, client, tx)
 	if err != nil {
-		return fmt.Errorf("failed to wait for transaction to be mined: %v", err)
+		return nil, fmt.Errorf("failed to wait for transaction to be mined: %w", err)
+	}
+	receipt, err = waitForConfirmations(ctx, client, receipt)
+	if err != nil {
+		return nil, err
 	}
 
 	if receipt.Status != 1 {
-		return fmt.Errorf("transaction failed with status: %v", receipt.Status)
+		return receipt, withCode(errCodeTxFailed, fmt.Errorf("transaction failed with status: %v", receipt.Status))
 	}
 
-	// fmt.Println("Transaction mined successfully with receipt:", receipt)
-	return nil
+	return receipt, nil
 }
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+const (
+	// reservationGrace is how long a reservation above the node's pending nonce is
+	// trusted, to cover another process that reserved a nonce but has not sent yet
+	reservationGrace = 30 * time.Second
+	lockTimeout      = 5 * time.Second
+)
+
+var (
+	nonceStore string
+
+	nonceManagersMu sync.Mutex
+	nonceManagers   = make(map[string]*nonceManager)
+)
+
+// nonceReservation is the per (chainID, address) file in --nonce-store.
+type nonceReservation struct {
+	Next      uint64 `json:"next"`
+	UpdatedAt int64  `json:"updatedAt"`
+}
+
+// nonceManager hands out consecutive nonces for one account, starting from the
+// node's pending nonce, so a process can submit many transactions without
+// waiting for each to be mined. With --nonce-store the reservations are shared
+// with other processes through a file.
+type nonceManager struct {
+	mu      sync.Mutex
+	client  *ethclient.Client
+	address common.Address
+	path    string
+	next    uint64
+	loaded  bool
+}
+
+func registerNonceFlags() {
+	rootCmd.PersistentFlags().StringVar(&nonceStore, "nonce-store", "", "Directory for nonce reservation files shared between processes")
+}
+
+// nonceManagerFor returns the process wide nonce manager of address on chainID.
+func nonceManagerFor(client *ethclient.Client, chainID *big.Int, address common.Address) *nonceManager {
+	key := chainID.String() + "-" + strings.ToLower(address.Hex())
+	nonceManagersMu.Lock()
+	defer nonceManagersMu.Unlock()
+	if m, ok := nonceManagers[key]; ok {
+		return m
+	}
+	m := &nonceManager{client: client, address: address}
+	if nonceStore != "" {
+		m.path = filepath.Join(nonceStore, key+".json")
+	}
+	nonceManagers[key] = m
+	return m
+}
+
+// Next reserves and returns the next nonce.
+func (m *nonceManager) Next(ctx context.Context) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.path == "" {
+		if !m.loaded {
+			pending, err := m.client.PendingNonceAt(ctx, m.address)
+			if err != nil {
+				return 0, err
+			}
+			m.next = pending
+			m.loaded = true
+		}
+		nonce := m.next
+		m.next++
+		return nonce, nil
+	}
+
+	unlock, err := lockFile(m.path + ".lock")
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	pending, err := m.client.PendingNonceAt(ctx, m.address)
+	if err != nil {
+		return 0, err
+	}
+	reservation, err := readReservation(m.path)
+	if err != nil {
+		return 0, err
+	}
+	nonce := pending
+	if m.loaded && m.next > nonce {
+		nonce = m.next
+	}
+	if reservation.Next > nonce {
+		age := time.Since(time.Unix(reservation.UpdatedAt, 0))
+		if age < reservationGrace {
+			nonce = reservation.Next
+		} else {
+			fmt.Fprintf(textOut, "Nonce gap detected for %s: reserved up to %d but the node's pending nonce is %d, resuming from %d\n", m.address.Hex(), reservation.Next, pending, nonce)
+		}
+	}
+	m.next = nonce + 1
+	m.loaded = true
+	if err := writeReservation(m.path, nonceReservation{Next: m.next, UpdatedAt: time.Now().Unix()}); err != nil {
+		return 0, err
+	}
+	return nonce, nil
+}
+
+// Release gives back a nonce whose transaction was never sent, if it was the last one handed out.
+func (m *nonceManager) Release(nonce uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.loaded || m.next != nonce+1 {
+		return
+	}
+	m.next = nonce
+	if m.path != "" {
+		if unlock, err := lockFile(m.path + ".lock"); err == nil {
+			reservation, err := readReservation(m.path)
+			if err == nil && reservation.Next == nonce+1 {
+				_ = writeReservation(m.path, nonceReservation{Next: nonce, UpdatedAt: time.Now().Unix()})
+			}
+			unlock()
+		}
+	}
+}
+
+// Resync drops the local state after the node rejected a nonce, so the next
+// reservation starts again from the node's pending nonce.
+func (m *nonceManager) Resync() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.loaded = false
+	if m.path != "" {
+		if unlock, err := lockFile(m.path + ".lock"); err == nil {
+			_ = os.Remove(m.path)
+			unlock()
+		}
+	}
+}
+
+// isNonceError reports whether the node rejected a transaction because of its nonce.
+func isNonceError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "nonce too low") || strings.Contains(msg, "nonce too high") || strings.Contains(msg, "invalid nonce")
+}
+
+func readReservation(path string) (nonceReservation, error) {
+	var reservation nonceReservation
+	raw, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return reservation, nil
+	}
+	if err != nil {
+		return reservation, err
+	}
+	if err := json.Unmarshal(raw, &reservation); err != nil {
+		return reservation, fmt.Errorf("invalid nonce reservation file %s: %v", path, err)
+	}
+	return reservation, nil
+}
+
+func writeReservation(path string, reservation nonceReservation) error {
+	raw, err := json.Marshal(reservation)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// lockFile takes an exclusive lock by creating path, breaking locks left behind
+// by a crashed process.
+func lockFile(path string) (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(path) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+		if info, statErr := os.Stat(path); statErr == nil && time.Since(info.ModTime()) > reservationGrace {
+			os.Remove(path)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for nonce lock %s", path)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	offline        bool
+	offlineNonce   uint64
+	offlineChainID uint64
+	gasPrice       string
+	offlineOut     string
+)
+
+// offlineTx is the file written by --offline and read back by broadcast.
+type offlineTx struct {
+	Label   string         `json:"label"`
+	Raw     hexutil.Bytes  `json:"raw"`
+	Hash    common.Hash    `json:"hash"`
+	From    common.Address `json:"from"`
+	To      common.Address `json:"to"`
+	ChainID uint64         `json:"chainId"`
+	Nonce   uint64         `json:"nonce"`
+	Gas     uint64         `json:"gas"`
+	Fees    txFees         `json:"fees"`
+	Method  string         `json:"method"`
+	Args    []decodedArg   `json:"args"`
+}
+
+var broadcastCmd = &cobra.Command{
+	Use:   "broadcast <file>",
+	Short: "Broadcast a transaction signed with --offline",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rpcUrl, _ := cmd.Flags().GetString("rpcUrl")
+		err := broadcast_(rpcUrl, args[0])
+		if err != nil {
+			return failf("Failed to broadcast: %v", err)
+		}
+		return nil
+	},
+}
+
+func registerOfflineCommands() {
+	rootCmd.PersistentFlags().BoolVar(&offline, "offline", false, "Sign without connecting to a node and write the tx to --out")
+	rootCmd.PersistentFlags().Uint64Var(&offlineNonce, "nonce", 0, "Nonce of the transaction, required with --offline, taken from the node otherwise")
+	rootCmd.PersistentFlags().Uint64Var(&offlineChainID, "chain-id", 0, "EVM chain ID, required with --offline")
+	rootCmd.PersistentFlags().StringVar(&gasPrice, "gas-price", "", "Gas price in wei for legacy txs, defaults to the node's suggestion")
+	rootCmd.PersistentFlags().StringVar(&offlineOut, "out", "signed-tx.json", "Output file of --offline and --build-only")
+
+	rootCmd.AddCommand(broadcastCmd)
+	broadcastCmd.Flags().String("rpcUrl", "http://localhost:8545", "Exocore RPC URL")
+}
+
+func signOffline(signer Signer, label string, to common.Address, contractAbi abi.ABI, data []byte) error {
+	if !rootCmd.PersistentFlags().Changed("nonce") || offlineChainID == 0 {
+		return fmt.Errorf("--offline requires --nonce and --chain-id")
+	}
+	fees, err := resolveFees(context.Background(), nil)
+	if err != nil {
+		return err
+	}
+	chainID := new(big.Int).SetUint64(offlineChainID)
+
+	gasLimit := uint64(defaultGasLimit)
+	if gasLimitFlag != 0 {
+		gasLimit = gasLimitFlag
+	}
+	tx := newTx(chainID, offlineNonce, to, gasLimit, fees, data)
+	signTx, err := signer.SignTx(tx, chainID)
+	if err != nil {
+		return err
+	}
+	raw, err := signTx.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	method, args, err := decodeCallArgs(contractAbi, data)
+	if err != nil {
+		return err
+	}
+	record := offlineTx{
+		Label:   label,
+		Raw:     raw,
+		Hash:    signTx.Hash(),
+		From:    signer.Address(),
+		To:      to,
+		ChainID: offlineChainID,
+		Nonce:   offlineNonce,
+		Gas:     signTx.Gas(),
+		Fees:    fees,
+		Method:  method.Sig,
+		Args:    args,
+	}
+	out, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(offlineOut, out, 0o600); err != nil {
+		return err
+	}
+
+	printDecodedArgs(method, args)
+	fmt.Fprintf(textOut, "From: %s To: %s Nonce: %d Gas: %d ChainID: %d\n", record.From.Hex(), to.Hex(), offlineNonce, record.Gas, offlineChainID)
+	fmt.Fprintln(textOut, "Fees:", fees)
+	fmt.Fprintln(textOut, label, "signed offline, Transaction ID:", signTx.Hash().Hex())
+	fmt.Fprintln(textOut, "Signed transaction written to", offlineOut)
+	recordTx(signTx)
+	report.Status = "signed"
+	setResult(map[string]string{"out": offlineOut})
+	return nil
+}
+
+// readSignedTx accepts either the JSON file written by --offline or a bare hex encoded tx.
+func readSignedTx(path string) (*types.Transaction, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var raw []byte
+	var record offlineTx
+	if err := json.Unmarshal(content, &record); err == nil && len(record.Raw) > 0 {
+		raw = record.Raw
+	} else {
+		raw, err = hexutil.Decode(strings.TrimSpace(string(content)))
+		if err != nil {
+			return nil, fmt.Errorf("%s is neither an offline tx file nor a hex encoded tx: %v", path, err)
+		}
+	}
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(raw); err != nil {
+		return nil, fmt.Errorf("invalid signed transaction: %v", err)
+	}
+	return tx, nil
+}
+
+func broadcast_(rpcUrl, path string) error {
+	tx, err := readSignedTx(path)
+	if err != nil {
+		return err
+	}
+	if tx.To() == nil {
+		return fmt.Errorf("%s is a contract creation, broadcast only sends calls", path)
+	}
+
+	_, ethClient, err := connectToEthereum(rpcUrl)
+	if err != nil {
+		return err
+	}
+
+	chainID, err := ethClient.ChainID(context.Background())
+	if err != nil {
+		return err
+	}
+	if tx.ChainId().Cmp(chainID) != 0 {
+		return fmt.Errorf("transaction was signed for chain %s but the node is on chain %s", tx.ChainId(), chainID)
+	}
+
+	err = ethClient.SendTransaction(context.Background(), tx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(textOut, "Broadcast Transaction ID:", tx.Hash().Hex())
+	recordTx(tx)
+	report.Status = "sent"
+	if contractAbi, ok := precompileABI(*tx.To()); ok {
+		recordCall("Broadcast", *tx.To(), contractAbi, tx.Data())
+	}
+	if noWait {
+		return nil
+	}
+	receipt, err := waitForTransaction(ethClient, tx.Hash().Hex())
+	if receipt != nil {
+		summary := summarizeReceipt(receipt)
+		report.Receipt = &summary
+		printReceipt(summary)
+	}
+	if err != nil {
+		return err
+	}
+	report.Status = "mined"
+
+	contractAbi, ok := precompileABI(*tx.To())
+	if !ok {
+		return nil
+	}
+	sender, err := types.Sender(types.LatestSignerForChainID(chainID), tx)
+	if err != nil {
+		return err
+	}
+	state, err := postTxState(context.Background(), ethClient, sender, *tx.To(), contractAbi, tx.Data(), receipt)
+	if err != nil {
+		return err
+	}
+	if state != nil {
+		report.Outputs = state.Outputs
+		report.OutputsBlock = state.Block
+		printPostTxState(state)
+	}
+	return nil
+}
@@ -0,0 +1,265 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/spf13/cobra"
+)
+
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
+// stable error codes of --output json, each with its own exit code
+const (
+	errCodeGeneric          = "error"
+	errCodeInvalidArguments = "invalid_arguments"
+	errCodeSimulationFailed = "simulation_failed"
+	errCodeTxFailed         = "tx_failed"
+	errCodeTimeout          = "timeout"
+	errCodeRPC              = "rpc_error"
+	errCodeSigner           = "signer_error"
+)
+
+var exitCodes = map[string]int{
+	errCodeGeneric:          1,
+	errCodeInvalidArguments: 2,
+	errCodeSimulationFailed: 3,
+	errCodeTxFailed:         4,
+	errCodeTimeout:          5,
+	errCodeRPC:              6,
+	errCodeSigner:           7,
+}
+
+var (
+	outputFormat string
+	verbose      bool
+
+	// stdout is where the --output json object goes.
+	stdout io.Writer = os.Stdout
+	// textOut receives the human readable output. It is stderr with --output
+	// json, so stdout only carries the report.
+	textOut io.Writer = os.Stdout
+
+	// report collects what the command did, printed as one object with --output json.
+	report = &commandReport{}
+	// reportMu guards the tx fields of report, written by concurrent sends in bulk.
+	reportMu sync.Mutex
+)
+
+// commandReport is the --output json object of a command.
+type commandReport struct {
+	Command    string            `json:"command"`
+	OK         bool              `json:"ok"`
+	Status     string            `json:"status,omitempty"`
+	Label      string            `json:"label,omitempty"`
+	Method     string            `json:"method,omitempty"`
+	Precompile *common.Address   `json:"precompile,omitempty"`
+	Args       []decodedArg      `json:"args,omitempty"`
+	Data       hexutil.Bytes     `json:"data,omitempty"`
+	From       *common.Address   `json:"from,omitempty"`
+	TxHash     string            `json:"txHash,omitempty"`
+	Nonce      *uint64           `json:"nonce,omitempty"`
+	GasLimit   uint64            `json:"gasLimit,omitempty"`
+	Fees       *txFees           `json:"fees,omitempty"`
+	Simulation *simulationResult `json:"simulation,omitempty"`
+	Receipt    *receiptSummary   `json:"receipt,omitempty"`
+	Outputs    []decodedArg      `json:"outputs,omitempty"`
+	// OutputsBlock is the parent block Outputs were simulated on
+	OutputsBlock uint64       `json:"outputsSimulatedAtBlock,omitempty"`
+	Result       interface{}  `json:"result,omitempty"`
+	Error        *reportError `json:"error,omitempty"`
+}
+
+type reportError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// codedError tags an error with one of the stable error codes.
+type codedError struct {
+	code string
+	err  error
+}
+
+func (e *codedError) Error() string { return e.err.Error() }
+func (e *codedError) Unwrap() error { return e.err }
+
+func withCode(code string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &codedError{code: code, err: err}
+}
+
+func registerOutputFlags() {
+	rootCmd.PersistentFlags().StringVar(&outputFormat, "output", outputText, "Output format: text or json (one object per command on stdout, text goes to stderr)")
+	rootCmd.PersistentFlags().BoolVar(&verbose, "verbose", false, "Print debug output such as padded addresses")
+}
+
+// setupOutput switches to --output json once the flags are parsed.
+func setupOutput(cmd *cobra.Command) error {
+	report.Command = cmd.CommandPath()
+	switch outputFormat {
+	case outputText:
+	case outputJSON:
+		textOut = os.Stderr
+	default:
+		return withCode(errCodeInvalidArguments, fmt.Errorf("unknown --output %q, expected text or json", outputFormat))
+	}
+	return nil
+}
+
+// debugf prints only with --verbose.
+func debugf(format string, args ...interface{}) {
+	if verbose {
+		fmt.Fprintf(textOut, format, args...)
+	}
+}
+
+// recordCall notes the call a write command is about to make.
+func recordCall(label string, to common.Address, contractAbi abi.ABI, data []byte) {
+	report.Label = label
+	report.Precompile = &to
+	method, args, err := decodeCallArgs(contractAbi, data)
+	if err != nil {
+		return
+	}
+	report.Data = data
+	report.Method = method.Sig
+	report.Args = args
+}
+
+// recordTx notes a signed transaction, the last one wins for replacements.
+func recordTx(tx *types.Transaction) {
+	nonce := tx.Nonce()
+	fees := txFees{Type: txTypeDynamic, MaxFee: tx.GasFeeCap(), MaxPriorityFee: tx.GasTipCap()}
+	if tx.Type() == types.LegacyTxType {
+		fees = txFees{Type: txTypeLegacy, GasPrice: tx.GasPrice()}
+	}
+	reportMu.Lock()
+	defer reportMu.Unlock()
+	report.TxHash = tx.Hash().Hex()
+	report.Nonce = &nonce
+	report.GasLimit = tx.Gas()
+	report.Fees = &fees
+}
+
+// setResult attaches command specific data, such as query results, to the report.
+func setResult(result interface{}) {
+	report.Result = result
+}
+
+// finishReport prints the report of a successful command with --output json.
+func finishReport() {
+	if outputFormat != outputJSON {
+		return
+	}
+	report.OK = true
+	writeReport()
+}
+
+func writeReport() {
+	out, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to encode output:", err)
+		return
+	}
+	fmt.Fprintln(stdout, string(out))
+}
+
+// commandError is what a command's RunE returns: the message worded for the
+// user and the code of the underlying error. fatalf reports it once cobra
+// returns, and a plan step records it without ending the run.
+type commandError struct {
+	code    string
+	message string
+}
+
+func (e *commandError) Error() string { return e.message }
+
+// failf builds the commandError of a failing command, coded after the error
+// among args.
+func failf(format string, args ...interface{}) error {
+	return &commandError{code: errorCode(lastError(args)), message: fmt.Sprintf(format, args...)}
+}
+
+// lastError is the last error among the format args, nil if there is none.
+func lastError(args []interface{}) error {
+	var err error
+	for _, arg := range args {
+		if e, ok := arg.(error); ok {
+			err = e
+		}
+	}
+	return err
+}
+
+// fatalf ends the process with an error, exiting with the exit status of its
+// error code. Text output logs it as before; with --output json the report is
+// printed with the error code. It is only called once rootCmd.Execute returns.
+func fatalf(format string, args ...interface{}) {
+	code := errorCode(lastError(args))
+	if outputFormat != outputJSON {
+		log.Printf(format, args...)
+		os.Exit(exitCodes[code])
+	}
+	if report.Command == "" {
+		// cobra failed before the command ran, e.g. on an unknown flag
+		if cmd, _, findErr := rootCmd.Find(os.Args[1:]); findErr == nil {
+			report.Command = cmd.CommandPath()
+		}
+	}
+	report.OK = false
+	report.Error = &reportError{Code: code, Message: fmt.Sprintf(format, args...)}
+	writeReport()
+	os.Exit(exitCodes[code])
+}
+
+// errorCode classifies err: an explicit code wins, then timeouts and network
+// failures are recognized, anything else is a generic error.
+func errorCode(err error) string {
+	if err == nil {
+		return errCodeGeneric
+	}
+	var coded *codedError
+	if errors.As(err, &coded) {
+		return coded.code
+	}
+	var failed *commandError
+	if errors.As(err, &failed) {
+		return failed.code
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return errCodeTimeout
+	}
+	var netErr *net.OpError
+	if errors.As(err, &netErr) {
+		return errCodeRPC
+	}
+	return errCodeGeneric
+}
+
+// executeError codes an error returned by rootCmd.Execute. Commands return
+// coded errors, so an uncoded one is a bad flag or argument cobra rejected.
+func executeError(err error) error {
+	var coded *codedError
+	var failed *commandError
+	if errors.As(err, &coded) || errors.As(err, &failed) {
+		return err
+	}
+	return withCode(errCodeInvalidArguments, err)
+}
@@ -0,0 +1,314 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	stepOK      = "ok"
+	stepFailed  = "failed"
+	stepSkipped = "skipped"
+)
+
+// plan is the file run by the run command: defaults shared by all steps and
+// the steps in order.
+type plan struct {
+	Defaults        map[string]string `yaml:"defaults"`
+	ContinueOnError bool              `yaml:"continueOnError"`
+	Steps           []planStep        `yaml:"steps"`
+}
+
+// planStep is one command of a plan. Op is the command path, e.g. deposit or
+// tx wait, Params its flags without the dashes and Args its positional arguments.
+type planStep struct {
+	Name            string            `yaml:"name"`
+	Op              string            `yaml:"op"`
+	Params          map[string]string `yaml:"params"`
+	Args            []string          `yaml:"args"`
+	ContinueOnError *bool             `yaml:"continueOnError"`
+}
+
+// planLine is a line of a JSONL plan, either {"defaults": {...}} or a step.
+type planLine struct {
+	Defaults map[string]string `yaml:"defaults"`
+	planStep `yaml:",inline"`
+}
+
+// stepResult is the outcome of a step in the run report.
+type stepResult struct {
+	Index    int            `json:"index"`
+	Name     string         `json:"name,omitempty"`
+	Op       string         `json:"op"`
+	Status   string         `json:"status"`
+	Duration string         `json:"duration,omitempty"`
+	Error    *reportError   `json:"error,omitempty"`
+	Report   *commandReport `json:"report,omitempty"`
+}
+
+// stepFailure is the error of a failed step, from its command or its flags.
+type stepFailure struct {
+	Code    string
+	Message string
+}
+
+var runCmd = &cobra.Command{
+	Use:   "run <plan.yaml|plan.jsonl>",
+	Short: "Run the steps of a plan file in one process with a shared client and signer",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		continueOnError, _ := cmd.Flags().GetBool("continue-on-error")
+		reportFile, _ := cmd.Flags().GetString("report")
+		err := runPlan_(cmd, args[0], continueOnError, reportFile)
+		if err != nil {
+			return failf("Failed to run plan: %v", err)
+		}
+		return nil
+	},
+}
+
+func registerPlanCommands() {
+	rootCmd.AddCommand(runCmd)
+
+	runCmd.Flags().String("rpcUrl", "http://localhost:8545", "Exocore RPC URL, overrides the plan when set")
+	runCmd.Flags().Bool("continue-on-error", false, "Run the remaining steps after a failure, unless a step sets continueOnError: false")
+	runCmd.Flags().String("report", "", "Write the per-step results as JSON to this file")
+}
+
+// loadPlan reads a YAML plan, or a JSONL plan with one step per line.
+func loadPlan(path string) (*plan, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	p := &plan{}
+	if filepath.Ext(path) == ".jsonl" {
+		for i, line := range strings.Split(string(raw), "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			var entry planLine
+			if err := yaml.Unmarshal([]byte(line), &entry); err != nil {
+				return nil, fmt.Errorf("%s line %d: %v", path, i+1, err)
+			}
+			if entry.Defaults != nil {
+				if p.Defaults == nil {
+					p.Defaults = make(map[string]string)
+				}
+				for name, value := range entry.Defaults {
+					p.Defaults[name] = value
+				}
+			}
+			if entry.Op != "" {
+				p.Steps = append(p.Steps, entry.planStep)
+			}
+		}
+	} else if err := yaml.Unmarshal(raw, p); err != nil {
+		return nil, fmt.Errorf("invalid plan %s: %v", path, err)
+	}
+	if len(p.Steps) == 0 {
+		return nil, fmt.Errorf("plan %s has no steps", path)
+	}
+	for i, step := range p.Steps {
+		if step.Op == "" {
+			return nil, fmt.Errorf("step %d has no op", i+1)
+		}
+	}
+	return p, nil
+}
+
+func runPlan_(cmd *cobra.Command, path string, continueOnError bool, reportFile string) error {
+	p, err := loadPlan(path)
+	if err != nil {
+		return err
+	}
+
+	// flags given to run, such as --dry-run or --from, apply to every step
+	overrides := make(map[string]string)
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		switch f.Name {
+		case "continue-on-error", "report":
+			return
+		}
+		overrides[f.Name] = f.Value.String()
+	})
+	continueOnError = continueOnError || p.ContinueOnError
+
+	runReport := report
+	results := make([]stepResult, 0, len(p.Steps))
+	failed := 0
+	stopped := false
+	for i, step := range p.Steps {
+		result := stepResult{Index: i + 1, Name: step.Name, Op: step.Op}
+		if stopped {
+			result.Status = stepSkipped
+			results = append(results, result)
+			continue
+		}
+
+		fmt.Fprintf(textOut, "Step %d/%d: %s\n", i+1, len(p.Steps), stepTitle(step))
+		start := time.Now()
+		stepReport, failure := runStep(step, p.Defaults, overrides)
+		result.Duration = time.Since(start).Round(time.Millisecond).String()
+		result.Report = stepReport
+		if failure != nil {
+			result.Status = stepFailed
+			result.Error = &reportError{Code: failure.Code, Message: failure.Message}
+			stepReport.Error = result.Error
+			failed++
+			fmt.Fprintf(textOut, "Step %d failed: %s\n", i+1, failure.Message)
+			stepContinue := continueOnError
+			if step.ContinueOnError != nil {
+				stepContinue = *step.ContinueOnError
+			}
+			if !stepContinue {
+				stopped = true
+			}
+		} else {
+			result.Status = stepOK
+			stepReport.OK = true
+		}
+		results = append(results, result)
+	}
+
+	// the steps reset the shared persistent flags, put back the ones given to run
+	if err := setStepFlags(cmd, overrides, false); err != nil {
+		return err
+	}
+	report = runReport
+	setResult(results)
+	printPlanResults(results)
+	if reportFile != "" {
+		out, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(reportFile, out, 0o644); err != nil {
+			return err
+		}
+		fmt.Fprintln(textOut, "Report written to", reportFile)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d steps failed", failed, len(p.Steps))
+	}
+	return nil
+}
+
+// runStep runs one step through its cobra command, with the flags reset and
+// then set from the plan defaults, the step params and the run overrides.
+func runStep(step planStep, defaults, overrides map[string]string) (*commandReport, *stepFailure) {
+	stepReport := &commandReport{}
+	report = stepReport
+
+	cmd, rest, err := rootCmd.Find(strings.Fields(step.Op))
+	if err != nil || cmd == rootCmd || len(rest) > 0 {
+		return stepReport, &stepFailure{Code: errCodeInvalidArguments, Message: fmt.Sprintf("unknown op %q", step.Op)}
+	}
+	// runCmd itself is matched by name, referring to it here would be an initialization cycle
+	if (cmd.Parent() == rootCmd && cmd.Name() == "run") || cmd.RunE == nil {
+		return stepReport, &stepFailure{Code: errCodeInvalidArguments, Message: fmt.Sprintf("%q cannot be run from a plan", step.Op)}
+	}
+
+	resetFlags(cmd)
+	if err := setStepFlags(cmd, defaults, false); err != nil {
+		return stepReport, &stepFailure{Code: errCodeInvalidArguments, Message: err.Error()}
+	}
+	if err := setStepFlags(cmd, step.Params, true); err != nil {
+		return stepReport, &stepFailure{Code: errCodeInvalidArguments, Message: err.Error()}
+	}
+	if err := setStepFlags(cmd, overrides, false); err != nil {
+		return stepReport, &stepFailure{Code: errCodeInvalidArguments, Message: err.Error()}
+	}
+
+	// the checks cobra runs before the command's hooks when executing it
+	if err := cmd.ValidateArgs(step.Args); err != nil {
+		return stepReport, &stepFailure{Code: errCodeInvalidArguments, Message: err.Error()}
+	}
+	if err := validateRequiredFlags(cmd); err != nil {
+		return stepReport, &stepFailure{Code: errCodeInvalidArguments, Message: err.Error()}
+	}
+	if err := rootCmd.PersistentPreRunE(cmd, step.Args); err != nil {
+		return stepReport, &stepFailure{Code: errorCode(err), Message: err.Error()}
+	}
+	if err := cmd.RunE(cmd, step.Args); err != nil {
+		return stepReport, &stepFailure{Code: errorCode(err), Message: err.Error()}
+	}
+	return stepReport, nil
+}
+
+// validateRequiredFlags reports the flags marked required that were not set,
+// with the error cobra gives on the command line. This cobra version does not
+// export its own check.
+func validateRequiredFlags(cmd *cobra.Command) error {
+	var missing []string
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if flagRequired(f) && !f.Changed {
+			missing = append(missing, f.Name)
+		}
+	})
+	if len(missing) > 0 {
+		return fmt.Errorf(`required flag(s) "%s" not set`, strings.Join(missing, `", "`))
+	}
+	return nil
+}
+
+// resetFlags puts every flag of cmd, inherited ones included, back to its
+// default so one step's flags do not leak into the next.
+func resetFlags(cmd *cobra.Command) {
+	reset := func(f *pflag.Flag) {
+		_ = f.Value.Set(f.DefValue)
+		f.Changed = false
+	}
+	cmd.LocalFlags().VisitAll(reset)
+	cmd.InheritedFlags().VisitAll(reset)
+}
+
+// setStepFlags sets values as if given on the command line. Unknown flags are
+// an error when strict, and skipped otherwise.
+func setStepFlags(cmd *cobra.Command, values map[string]string, strict bool) error {
+	for name, value := range values {
+		if cmd.Flags().Lookup(name) == nil {
+			if strict {
+				return fmt.Errorf("%s has no --%s flag", cmd.CommandPath(), name)
+			}
+			continue
+		}
+		if err := cmd.Flags().Set(name, value); err != nil {
+			return fmt.Errorf("--%s: %v", name, err)
+		}
+	}
+	return nil
+}
+
+func stepTitle(step planStep) string {
+	if step.Name != "" {
+		return fmt.Sprintf("%s (%s)", step.Name, step.Op)
+	}
+	return step.Op
+}
+
+func printPlanResults(results []stepResult) {
+	fmt.Fprintln(textOut, "Plan results:")
+	for _, result := range results {
+		line := fmt.Sprintf("  %2d %-30s %-7s", result.Index, stepTitle(planStep{Name: result.Name, Op: result.Op}), result.Status)
+		if result.Duration != "" {
+			line += " " + result.Duration
+		}
+		if result.Report != nil && result.Report.TxHash != "" {
+			line += " " + result.Report.TxHash
+		}
+		if result.Error != nil {
+			line += " [" + result.Error.Code + "] " + result.Error.Message
+		}
+		fmt.Fprintln(textOut, line)
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// planTestCommand adds "plantest child" to rootCmd for the duration of the
+// test. child requires --need and records the flags of each run.
+func planTestCommand(t *testing.T) *[]map[string]string {
+	var runs []map[string]string
+	parent := &cobra.Command{Use: "plantest"}
+	parent.PersistentFlags().String("inherited", "default", "")
+	child := &cobra.Command{
+		Use:  "child",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			need, _ := cmd.Flags().GetString("need")
+			inherited, _ := cmd.Flags().GetString("inherited")
+			runs = append(runs, map[string]string{"need": need, "inherited": inherited})
+			return nil
+		},
+	}
+	child.Flags().String("need", "", "")
+	if err := child.MarkFlagRequired("need"); err != nil {
+		t.Fatal(err)
+	}
+	parent.AddCommand(child)
+	rootCmd.AddCommand(parent)
+	t.Cleanup(func() { rootCmd.RemoveCommand(parent) })
+	setGlobal(t, &rootCmd.PersistentPreRunE, func(*cobra.Command, []string) error { return nil })
+	setGlobal(t, &report, report)
+	return &runs
+}
+
+func TestRunStepChecksArgsAndRequiredFlags(t *testing.T) {
+	runs := planTestCommand(t)
+	tests := []struct {
+		name string
+		step planStep
+		want string
+	}{
+		{"missing required flag", planStep{Op: "plantest child"}, `required flag(s) "need" not set`},
+		{"extra argument", planStep{Op: "plantest child", Params: map[string]string{"need": "1"}, Args: []string{"x"}}, "unknown command"},
+		{"unknown flag", planStep{Op: "plantest child", Params: map[string]string{"need": "1", "other": "1"}}, "no --other flag"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, failure := runStep(tt.step, nil, nil)
+			if failure == nil || failure.Code != errCodeInvalidArguments || !strings.Contains(failure.Message, tt.want) {
+				t.Fatalf("failure = %+v, want %s mentioning %q", failure, errCodeInvalidArguments, tt.want)
+			}
+		})
+	}
+	if len(*runs) != 0 {
+		t.Fatalf("the command ran %d times despite invalid steps", len(*runs))
+	}
+}
+
+func TestRunStepResetsFlagsBetweenSteps(t *testing.T) {
+	runs := planTestCommand(t)
+	steps := []planStep{
+		{Op: "plantest child", Params: map[string]string{"need": "1", "inherited": "changed"}},
+		{Op: "plantest child", Params: map[string]string{"need": "2"}},
+	}
+	for _, step := range steps {
+		if _, failure := runStep(step, nil, nil); failure != nil {
+			t.Fatalf("runStep: %+v", failure)
+		}
+	}
+	want := []map[string]string{{"need": "1", "inherited": "changed"}, {"need": "2", "inherited": "default"}}
+	if len(*runs) != len(want) {
+		t.Fatalf("got %d runs, want %d", len(*runs), len(want))
+	}
+	for i, run := range *runs {
+		if run["need"] != want[i]["need"] || run["inherited"] != want[i]["inherited"] {
+			t.Errorf("run %d flags = %v, want %v", i, run, want[i])
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+)
+
+var queryCmd = &cobra.Command{
+	Use:   "query",
+	Short: "Read-only queries against the precompiles",
+}
+
+var getClientChainsCmd = &cobra.Command{
+	Use:   "client-chains",
+	Short: "List the client chains registered in Exocore",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rpcUrl, _ := cmd.Flags().GetString("rpcUrl")
+		chains, err := getClientChains_(rpcUrl)
+		if err != nil {
+			return failf("Failed to get client chains: %v", err)
+		}
+		setResult(map[string]interface{}{"clientChains": chains})
+		fmt.Fprintf(textOut, "%d registered client chains\n", len(chains))
+		for _, id := range chains {
+			fmt.Fprintln(textOut, id)
+		}
+		return nil
+	},
+}
+
+var isRegisteredClientChainCmd = &cobra.Command{
+	Use:   "is-registered-client-chain",
+	Short: "Check if a client chain is registered in Exocore",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rpcUrl, _ := cmd.Flags().GetString("rpcUrl")
+		clientChainID, _ := cmd.Flags().GetUint32("clientChainID")
+		registered, err := isRegisteredClientChain_(rpcUrl, clientChainID)
+		if err != nil {
+			return failf("Failed to check if client chain is registered: %v", err)
+		}
+		setResult(map[string]interface{}{"clientChainID": clientChainID, "registered": registered})
+		if registered {
+			fmt.Fprintf(textOut, "Client chain %d is registered\n", clientChainID)
+		} else {
+			fmt.Fprintf(textOut, "Client chain %d is not registered\n", clientChainID)
+		}
+		return nil
+	},
+}
+
+func registerQueryCommands() {
+	rootCmd.AddCommand(queryCmd)
+	queryCmd.AddCommand(getClientChainsCmd)
+	queryCmd.AddCommand(isRegisteredClientChainCmd)
+
+	getClientChainsCmd.Flags().String("rpcUrl", "http://localhost:8545", "Exocore RPC URL")
+
+	isRegisteredClientChainCmd.Flags().String("rpcUrl", "http://localhost:8545", "Exocore RPC URL")
+	isRegisteredClientChainCmd.Flags().Uint32("clientChainID", 0, "Client chain ID")
+}
+
+// callPrecompile runs a read-only call of method on the precompile at to and
+// returns the unpacked outputs. The leading success flag the precompiles return
+// is checked and stripped.
+func callPrecompile(rpcUrl string, to common.Address, contractAbi abi.ABI, method string, args ...interface{}) ([]interface{}, error) {
+	_, ethClient, err := connectToEthereum(rpcUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := contractAbi.Pack(method, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := ethereum.CallMsg{
+		To:   &to,
+		Data: data,
+	}
+
+	result, err := ethClient.CallContract(context.Background(), msg, nil)
+	if err != nil {
+		if reason, ok := revertReason(err); ok {
+			return nil, fmt.Errorf("%s reverted: %s", method, reason)
+		}
+		return nil, err
+	}
+
+	values, err := contractAbi.Unpack(method, result)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) > 0 {
+		if success, ok := values[0].(bool); ok {
+			if !success {
+				return nil, fmt.Errorf("%s returned success=false", method)
+			}
+			values = values[1:]
+		}
+	}
+	return values, nil
+}
+
+func getClientChains_(rpcUrl string) ([]uint32, error) {
+	depositAbi, err := abi.JSON(strings.NewReader(DepositABI))
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := callPrecompile(rpcUrl, common.HexToAddress(depositPrecompileAddress), depositAbi, "getClientChains")
+	if err != nil {
+		return nil, err
+	}
+	return values[0].([]uint32), nil
+}
+
+func isRegisteredClientChain_(rpcUrl string, clientChainID uint32) (bool, error) {
+	depositAbi, err := abi.JSON(strings.NewReader(DepositABI))
+	if err != nil {
+		return false, err
+	}
+
+	values, err := callPrecompile(rpcUrl, common.HexToAddress(depositPrecompileAddress), depositAbi, "isRegisteredClientChain", clientChainID)
+	if err != nil {
+		return false, err
+	}
+	return values[0].(bool), nil
+}
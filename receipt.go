@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// receiptSummary is the part of a receipt worth reporting after a transaction.
+type receiptSummary struct {
+	Status            uint64       `json:"status"`
+	BlockNumber       uint64       `json:"blockNumber"`
+	GasUsed           uint64       `json:"gasUsed"`
+	EffectiveGasPrice string       `json:"effectiveGasPrice,omitempty"`
+	Logs              []decodedLog `json:"logs,omitempty"`
+}
+
+// decodedLog is a receipt log, with the event name and arguments when one of
+// the precompile ABIs knows the event.
+type decodedLog struct {
+	Address common.Address `json:"address"`
+	Event   string         `json:"event,omitempty"`
+	Args    []decodedArg   `json:"args,omitempty"`
+	Topics  []common.Hash  `json:"topics,omitempty"`
+	Data    string         `json:"data,omitempty"`
+}
+
+// precompileABI returns the ABI of the precompile deployed at address.
+func precompileABI(address common.Address) (abi.ABI, bool) {
+	var definition string
+	switch address {
+	case common.HexToAddress(depositPrecompileAddress):
+		definition = DepositABI
+	case common.HexToAddress(delegatePrecompileAddress):
+		definition = DelegateABI
+	case common.HexToAddress(rewardPrecompileAddress):
+		definition = rewardABI
+	default:
+		return abi.ABI{}, false
+	}
+	parsed, err := abi.JSON(strings.NewReader(definition))
+	if err != nil {
+		return abi.ABI{}, false
+	}
+	return parsed, true
+}
+
+func summarizeReceipt(receipt *types.Receipt) receiptSummary {
+	summary := receiptSummary{
+		Status:            receipt.Status,
+		GasUsed:           receipt.GasUsed,
+		EffectiveGasPrice: decimalString(receipt.EffectiveGasPrice),
+	}
+	if receipt.BlockNumber != nil {
+		summary.BlockNumber = receipt.BlockNumber.Uint64()
+	}
+	for _, log := range receipt.Logs {
+		summary.Logs = append(summary.Logs, decodeLog(log))
+	}
+	return summary
+}
+
+func decodeLog(log *types.Log) decodedLog {
+	decoded := decodedLog{Address: log.Address, Topics: log.Topics, Data: common.Bytes2Hex(log.Data)}
+	contractAbi, ok := precompileABI(log.Address)
+	if !ok || len(log.Topics) == 0 {
+		return decoded
+	}
+	event, err := contractAbi.EventByID(log.Topics[0])
+	if err != nil {
+		return decoded
+	}
+	values, err := event.Inputs.NonIndexed().Unpack(log.Data)
+	if err != nil {
+		return decoded
+	}
+	decoded.Event = event.Sig
+	decoded.Args = namedValues(event.Inputs.NonIndexed(), values)
+	decoded.Topics = log.Topics[1:]
+	decoded.Data = ""
+	return decoded
+}
+
+func printReceipt(summary receiptSummary) {
+	fmt.Fprintf(textOut, "Receipt: status=%d block=%d gasUsed=%d effectiveGasPrice=%s\n", summary.Status, summary.BlockNumber, summary.GasUsed, summary.EffectiveGasPrice)
+	for _, log := range summary.Logs {
+		if log.Event == "" {
+			fmt.Fprintf(textOut, "  log %s topics=%v data=0x%s\n", log.Address.Hex(), log.Topics, log.Data)
+			continue
+		}
+		fmt.Fprintf(textOut, "  event %s\n", log.Event)
+		for _, arg := range log.Args {
+			fmt.Fprintf(textOut, "    %s: %v\n", arg.Name, arg.Value)
+		}
+	}
+}
+
+// postTxState replays the mined call on the parent of its block to recover
+// outputs such as latestAssetState or actualWithdrawAmount. The parent state
+// misses earlier transactions of the same block, so when the sender had any
+// the outputs would be stale and are skipped. Transactions of other senders
+// touching the same staker are not detected, hence the "simulated on the
+// parent block" label. It returns nil for methods that report nothing beyond success.
+func postTxState(ctx context.Context, client *ethclient.Client, from, to common.Address, contractAbi abi.ABI, data []byte, receipt *types.Receipt) (*simulationResult, error) {
+	if len(data) < 4 {
+		return nil, fmt.Errorf("calldata too short: %d bytes", len(data))
+	}
+	method, err := contractAbi.MethodById(data[:4])
+	if err != nil {
+		return nil, err
+	}
+	hasState := false
+	for _, output := range method.Outputs {
+		if output.Name != "success" {
+			hasState = true
+		}
+	}
+	if !hasState || receipt.BlockNumber == nil || receipt.BlockNumber.Sign() == 0 {
+		return nil, nil
+	}
+	parent := new(big.Int).Sub(receipt.BlockNumber, big.NewInt(1))
+	tx, _, err := client.TransactionByHash(ctx, receipt.TxHash)
+	if err != nil {
+		return nil, err
+	}
+	parentNonce, err := client.NonceAt(ctx, from, parent)
+	if err != nil {
+		return nil, err
+	}
+	if tx.Nonce() != parentNonce {
+		return &simulationResult{Error: fmt.Sprintf("%s has earlier transactions in block %s, outputs simulated on the parent block would be stale", from.Hex(), receipt.BlockNumber)}, nil
+	}
+	sim, err := simulate(ctx, client, from, to, contractAbi, data, parent)
+	if err != nil {
+		return nil, err
+	}
+	sim.Block = parent.Uint64()
+	return sim, nil
+}
+
+func printPostTxState(sim *simulationResult) {
+	if sim.Error != "" {
+		fmt.Fprintln(textOut, "Could not recover the transaction outputs:", sim.Error)
+		return
+	}
+	fmt.Fprintf(textOut, "Transaction outputs, simulated on the parent block %d:\n", sim.Block)
+	for _, output := range sim.Outputs {
+		fmt.Fprintf(textOut, "  %s: %v\n", output.Name, output.Value)
+	}
+}
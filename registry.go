@@ -0,0 +1,526 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	registryFile string
+	chainAlias   string
+	assetAlias   string
+)
+
+// registryChain is a client chain known to the local registry.
+type registryChain struct {
+	ID            uint32 `json:"lzId"`
+	Name          string `json:"name"`
+	AddressLength uint8  `json:"addressLength"`
+	SignatureType string `json:"signatureType"`
+}
+
+// registryAsset is a token known to the local registry, keyed by address and
+// the LayerZero ID of its client chain.
+type registryAsset struct {
+	Address    string `json:"address"`
+	ChainID    uint32 `json:"chainId"`
+	Symbol     string `json:"symbol"`
+	Decimals   uint8  `json:"decimals"`
+	OracleInfo string `json:"oracleInfo,omitempty"`
+}
+
+// registry is the local file of known client chains and tokens, seeded by
+// register-or-update-client-chain, register-token, register-reward-token and
+// import-from-chain, and editable with the registry commands.
+type registry struct {
+	Chains []registryChain `json:"chains"`
+	Assets []registryAsset `json:"assets"`
+}
+
+// builtinAssets are the assets every network has.
+var (
+	nstAsset  = registryAsset{Address: nstAssetAddress, Symbol: "ETH", Decimals: 18}
+	imuaAsset = registryAsset{Symbol: "IMUA", Decimals: 18}
+)
+
+// builtinChains are the LayerZero IDs of the client chains Exocore supports,
+// so --chain works with an empty registry.
+var builtinChains = []registryChain{
+	{ID: 101, Name: "mainnet", AddressLength: 20, SignatureType: "secp256k1"},
+	{ID: 40161, Name: "sepolia", AddressLength: 20, SignatureType: "secp256k1"},
+	{ID: 40217, Name: "holesky", AddressLength: 20, SignatureType: "secp256k1"},
+	{ID: 202, Name: "solana", AddressLength: 32, SignatureType: "ed25519"},
+}
+
+var registryCmd = &cobra.Command{
+	Use:   "registry",
+	Short: "Manage the local registry of tokens",
+}
+
+var registryAddAssetCmd = &cobra.Command{
+	Use:   "add-asset",
+	Short: "Add or update a token in the registry",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		address, _ := cmd.Flags().GetString("address")
+		symbol, _ := cmd.Flags().GetString("symbol")
+		decimals, _ := cmd.Flags().GetUint8("decimals")
+		oracleInfo, _ := cmd.Flags().GetString("oracleInfo")
+		err := registryAddAsset_(registryAsset{Address: address, ChainID: layerZeroID, Symbol: symbol, Decimals: decimals, OracleInfo: oracleInfo})
+		if err != nil {
+			return failf("Failed to add asset: %v", err)
+		}
+		return nil
+	},
+}
+
+var registryAddChainCmd = &cobra.Command{
+	Use:   "add-chain",
+	Short: "Add or update a client chain in the registry",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, _ := cmd.Flags().GetString("name")
+		addressLength, _ := cmd.Flags().GetUint8("addressLength")
+		signatureType, _ := cmd.Flags().GetString("signatureType")
+		err := registryAddChain_(registryChain{ID: layerZeroID, Name: name, AddressLength: addressLength, SignatureType: signatureType})
+		if err != nil {
+			return failf("Failed to add chain: %v", err)
+		}
+		return nil
+	},
+}
+
+var registryImportCmd = &cobra.Command{
+	Use:   "import-from-chain",
+	Short: "Add the client chains registered in Exocore to the registry",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rpcUrl, _ := cmd.Flags().GetString("rpcUrl")
+		err := registryImport_(rpcUrl)
+		if err != nil {
+			return failf("Failed to import client chains: %v", err)
+		}
+		return nil
+	},
+}
+
+var registryListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the client chains and tokens in the registry",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		err := registryList_()
+		if err != nil {
+			return failf("Failed to list registry: %v", err)
+		}
+		return nil
+	},
+}
+
+func registerRegistryCommands() {
+	rootCmd.PersistentFlags().StringVar(&registryFile, "registry", defaultRegistryPath(), "Registry file of known client chains and tokens")
+	rootCmd.PersistentFlags().StringVar(&chainAlias, "chain", "", "Client chain by registry name or LayerZero ID, e.g. holesky, instead of --layerZeroID / --clientChainID")
+	rootCmd.PersistentFlags().StringVar(&assetAlias, "asset", "", "Asset by registry symbol or address on the client chain, e.g. wstETH or nst, instead of --defaultAssetID / --assetAddress")
+
+	rootCmd.AddCommand(registryCmd)
+	registryCmd.AddCommand(registryAddAssetCmd)
+	registryCmd.AddCommand(registryAddChainCmd)
+	registryCmd.AddCommand(registryImportCmd)
+	registryCmd.AddCommand(registryListCmd)
+
+	registryAddAssetCmd.Flags().String("address", "", "Token address on the client chain (--layerZeroID)")
+	registryAddAssetCmd.Flags().String("symbol", "", "Token symbol")
+	registryAddAssetCmd.Flags().Uint8("decimals", 18, "Token decimals")
+	registryAddAssetCmd.Flags().String("oracleInfo", "", "Oracle info, e.g. ETH,Ethereum,8")
+
+	registryAddChainCmd.Flags().String("name", "", "Chain name used with --chain")
+	registryAddChainCmd.Flags().Uint8("addressLength", 20, "Address length in bytes")
+	registryAddChainCmd.Flags().String("signatureType", "secp256k1", "Signature type")
+
+	registryImportCmd.Flags().String("rpcUrl", "http://localhost:8545", "Exocore RPC URL")
+}
+
+func defaultRegistryPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".assetcli", "registry.json")
+	}
+	return filepath.Join(home, ".assetcli", "registry.json")
+}
+
+// loadRegistry reads --registry, an absent file being an empty registry.
+func loadRegistry() (*registry, error) {
+	reg := &registry{}
+	raw, err := os.ReadFile(registryFile)
+	if os.IsNotExist(err) {
+		return reg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, reg); err != nil {
+		return nil, fmt.Errorf("invalid registry file %s: %v", registryFile, err)
+	}
+	return reg, nil
+}
+
+func (r *registry) save() error {
+	raw, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(registryFile), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(registryFile, raw, 0o600)
+}
+
+// findAsset returns the token at address on chainID, or nil.
+func (r *registry) findAsset(address string, chainID uint32) *registryAsset {
+	if strings.EqualFold(address, nstAssetAddress) {
+		asset := nstAsset
+		asset.ChainID = chainID
+		return &asset
+	}
+	for i := range r.Assets {
+		if strings.EqualFold(r.Assets[i].Address, address) && r.Assets[i].ChainID == chainID {
+			return &r.Assets[i]
+		}
+	}
+	return nil
+}
+
+// findSymbol returns the token with symbol, preferring one on chainID.
+func (r *registry) findSymbol(symbol string, chainID uint32) *registryAsset {
+	var match *registryAsset
+	for i := range r.Assets {
+		if !strings.EqualFold(r.Assets[i].Symbol, symbol) {
+			continue
+		}
+		if r.Assets[i].ChainID == chainID {
+			return &r.Assets[i]
+		}
+		if match == nil {
+			match = &r.Assets[i]
+		}
+	}
+	if match == nil {
+		for _, builtin := range []registryAsset{nstAsset, imuaAsset} {
+			if strings.EqualFold(builtin.Symbol, symbol) {
+				asset := builtin
+				return &asset
+			}
+		}
+	}
+	return match
+}
+
+func (r *registry) upsertAsset(asset registryAsset) {
+	if existing := r.findAsset(asset.Address, asset.ChainID); existing != nil && !strings.EqualFold(asset.Address, nstAssetAddress) {
+		*existing = asset
+		return
+	}
+	r.Assets = append(r.Assets, asset)
+}
+
+// findChain returns the chain named name, or with that LayerZero ID, from the
+// registry or the builtin chains. An unknown numeric ID is returned bare.
+func (r *registry) findChain(name string) *registryChain {
+	id, err := strconv.ParseUint(name, 10, 32)
+	for _, chains := range [][]registryChain{r.Chains, builtinChains} {
+		for i := range chains {
+			if (err == nil && chains[i].ID == uint32(id)) || (chains[i].Name != "" && strings.EqualFold(chains[i].Name, name)) {
+				chain := chains[i]
+				return &chain
+			}
+		}
+	}
+	if err == nil {
+		return &registryChain{ID: uint32(id)}
+	}
+	return nil
+}
+
+func (r *registry) upsertChain(chain registryChain) {
+	for i := range r.Chains {
+		if r.Chains[i].ID == chain.ID {
+			r.Chains[i] = chain
+			return
+		}
+	}
+	r.Chains = append(r.Chains, chain)
+}
+
+// resolveAsset returns the asset named by --asset on chainID: nst, an address,
+// or a symbol registered on that chain.
+func (r *registry) resolveAsset(name string, chainID uint32) (*registryAsset, error) {
+	if strings.EqualFold(name, "nst") {
+		return r.findAsset(nstAssetAddress, chainID), nil
+	}
+	if strings.HasPrefix(name, "0x") {
+		if asset := r.findAsset(name, chainID); asset != nil {
+			return asset, nil
+		}
+		return &registryAsset{Address: name, ChainID: chainID}, nil
+	}
+	for i := range r.Assets {
+		if strings.EqualFold(r.Assets[i].Symbol, name) && r.Assets[i].ChainID == chainID {
+			return &r.Assets[i], nil
+		}
+	}
+	if strings.EqualFold(name, nstAsset.Symbol) {
+		return r.findAsset(nstAssetAddress, chainID), nil
+	}
+	return nil, fmt.Errorf("no asset %q on chain %d in the registry, add it with registry add-asset", name, chainID)
+}
+
+// chainFlags are the flags --chain fills, whichever of them the command has.
+var chainFlags = []string{"layerZeroID", "clientChainID", "rewardAssetChainID"}
+
+// assetFlags are the flags --asset fills with the asset address.
+var assetFlags = []string{"defaultAssetID", "assetAddress", "token"}
+
+// applyAliases resolves --chain and --asset through the registry into the
+// command's raw flags. Flags given explicitly must agree with the alias.
+func applyAliases(cmd *cobra.Command) error {
+	if chainAlias == "" && assetAlias == "" {
+		return nil
+	}
+	reg, err := loadRegistry()
+	if err != nil {
+		return err
+	}
+
+	if chainAlias != "" {
+		chain := reg.findChain(chainAlias)
+		if chain == nil {
+			return fmt.Errorf("--chain: unknown chain %q, add it with registry add-chain", chainAlias)
+		}
+		id := strconv.FormatUint(uint64(chain.ID), 10)
+		for _, name := range chainFlags {
+			if err := setAliasFlag(cmd, name, id, "--chain "+chainAlias); err != nil {
+				return err
+			}
+		}
+		if cmd == registerOrUpdateClientChainCmd && chain.Name != "" {
+			fields := map[string]string{
+				"name":          chain.Name,
+				"addressLength": strconv.Itoa(int(chain.AddressLength)),
+				"signatureType": chain.SignatureType,
+			}
+			for name, value := range fields {
+				if err := setAliasFlag(cmd, name, value, "--chain "+chainAlias); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if assetAlias != "" {
+		chainID, err := aliasChainID(cmd)
+		if err != nil {
+			return err
+		}
+		asset, err := reg.resolveAsset(assetAlias, chainID)
+		if err != nil {
+			return fmt.Errorf("--asset: %v", err)
+		}
+		for _, name := range assetFlags {
+			if err := setAliasFlag(cmd, name, asset.Address, "--asset "+assetAlias); err != nil {
+				return err
+			}
+		}
+		if (cmd == registerTokenCmd || cmd == registerRewardTokenCmd) && asset.Symbol != "" {
+			fields := map[string]string{
+				"decimals":   strconv.Itoa(int(asset.Decimals)),
+				"oracleInfo": asset.OracleInfo,
+				"symbol":     asset.Symbol,
+			}
+			if cmd == registerTokenCmd {
+				fields["name"] = asset.Symbol
+			}
+			for name, value := range fields {
+				if err := setAliasFlag(cmd, name, value, "--asset "+assetAlias); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// aliasChainID is the chain --asset resolves on: the command's own
+// clientChainID or rewardAssetChainID flag when it has one, else --layerZeroID.
+func aliasChainID(cmd *cobra.Command) (uint32, error) {
+	for _, name := range []string{"clientChainID", "rewardAssetChainID"} {
+		f := cmd.Flags().Lookup(name)
+		if f == nil {
+			continue
+		}
+		id, err := strconv.ParseUint(f.Value.String(), 10, 32)
+		if err != nil {
+			return 0, err
+		}
+		if id == 0 {
+			return 0, fmt.Errorf("--asset needs --chain or --%s", name)
+		}
+		return uint32(id), nil
+	}
+	return layerZeroID, nil
+}
+
+// setAliasFlag sets flag name of cmd to value unless the user set it, in which
+// case the two must match. Flags the command does not have are skipped.
+func setAliasFlag(cmd *cobra.Command, name, value, alias string) error {
+	f := cmd.Flags().Lookup(name)
+	if f == nil || value == "" {
+		return nil
+	}
+	if f.Changed {
+		if !strings.EqualFold(f.Value.String(), value) {
+			return fmt.Errorf("%s means --%s %s, but --%s %s was given", alias, name, value, name, f.Value.String())
+		}
+		return nil
+	}
+	return cmd.Flags().Set(name, value)
+}
+
+// lookupAsset returns the registry entry of address on chainID, or nil when it
+// is unknown or the registry cannot be read.
+func lookupAsset(address string, chainID uint32) *registryAsset {
+	reg, err := loadRegistry()
+	if err != nil {
+		fmt.Fprintln(textOut, "Ignoring registry:", err)
+		return nil
+	}
+	return reg.findAsset(address, chainID)
+}
+
+// recordAsset remembers a token registered on-chain so later amounts can use its decimals.
+// Only mined registrations are recorded, so --no-wait skips it as well.
+func recordAsset(asset registryAsset) {
+	if dryRun || buildOnly || offline || noWait {
+		return
+	}
+	if err := registryAddAsset_(asset); err != nil {
+		fmt.Fprintln(textOut, "Could not record token in the registry:", err)
+	}
+}
+
+func registryAddAsset_(asset registryAsset) error {
+	if _, err := assetToBytes(asset.Address); err != nil {
+		return err
+	}
+	if asset.Symbol == "" {
+		return fmt.Errorf("symbol is required")
+	}
+	reg, err := loadRegistry()
+	if err != nil {
+		return err
+	}
+	reg.upsertAsset(asset)
+	if err := reg.save(); err != nil {
+		return err
+	}
+	fmt.Fprintf(textOut, "Registry: %s on chain %d is %s with %d decimals\n", asset.Address, asset.ChainID, asset.Symbol, asset.Decimals)
+	return nil
+}
+
+// recordChain remembers a client chain registered on-chain so --chain can name it.
+// Like recordAsset it waits for the transaction to be mined.
+func recordChain(chain registryChain) {
+	if dryRun || buildOnly || offline || noWait {
+		return
+	}
+	if err := registryAddChain_(chain); err != nil {
+		fmt.Fprintln(textOut, "Could not record client chain in the registry:", err)
+	}
+}
+
+func registryAddChain_(chain registryChain) error {
+	if chain.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if _, err := strconv.ParseUint(chain.Name, 10, 32); err == nil {
+		return fmt.Errorf("name %q is a number, it would be taken for a LayerZero ID", chain.Name)
+	}
+	reg, err := loadRegistry()
+	if err != nil {
+		return err
+	}
+	reg.upsertChain(chain)
+	if err := reg.save(); err != nil {
+		return err
+	}
+	fmt.Fprintf(textOut, "Registry: chain %d is %s, %d byte addresses, %s signatures\n", chain.ID, chain.Name, chain.AddressLength, chain.SignatureType)
+	return nil
+}
+
+// registryImport_ adds every client chain registered in Exocore that the
+// registry does not have yet. The precompile only returns the IDs, so chains
+// that are not builtin are added without a name.
+func registryImport_(rpcUrl string) error {
+	ids, err := getClientChains_(rpcUrl)
+	if err != nil {
+		return err
+	}
+	reg, err := loadRegistry()
+	if err != nil {
+		return err
+	}
+	added := 0
+	for _, id := range ids {
+		known := false
+		for _, chain := range reg.Chains {
+			if chain.ID == id {
+				known = true
+			}
+		}
+		if known {
+			fmt.Fprintf(textOut, "Chain %d already in the registry\n", id)
+			continue
+		}
+		chain := *reg.findChain(strconv.FormatUint(uint64(id), 10))
+		reg.Chains = append(reg.Chains, chain)
+		added++
+		if chain.Name == "" {
+			fmt.Fprintf(textOut, "Added chain %d, name it with registry add-chain --layerZeroID %d --name ...\n", id, id)
+		} else {
+			fmt.Fprintf(textOut, "Added chain %d as %s\n", id, chain.Name)
+		}
+	}
+	if added == 0 {
+		return nil
+	}
+	return reg.save()
+}
+
+func registryList_() error {
+	reg, err := loadRegistry()
+	if err != nil {
+		return err
+	}
+	setResult(reg)
+	fmt.Fprintln(textOut, "Chains:")
+	listed := make(map[uint32]bool)
+	for _, chain := range reg.Chains {
+		listed[chain.ID] = true
+		fmt.Fprintf(textOut, "  %-10s lzId=%-6d addressLength=%d signatureType=%s\n", chain.Name, chain.ID, chain.AddressLength, chain.SignatureType)
+	}
+	for _, chain := range builtinChains {
+		if !listed[chain.ID] {
+			fmt.Fprintf(textOut, "  %-10s lzId=%-6d addressLength=%d signatureType=%s (builtin)\n", chain.Name, chain.ID, chain.AddressLength, chain.SignatureType)
+		}
+	}
+	fmt.Fprintln(textOut, "Assets:")
+	for _, asset := range reg.Assets {
+		fmt.Fprintf(textOut, "  %-8s %-66s chain=%d decimals=%d", asset.Symbol, asset.Address, asset.ChainID, asset.Decimals)
+		if asset.OracleInfo != "" {
+			fmt.Fprintf(textOut, " oracle=%s", asset.OracleInfo)
+		}
+		fmt.Fprintln(textOut)
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/external"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// signerBackend selects where transactions are signed: "local" for the key
+// sources resolved by loadSigner, "clef:<url>" or an http(s) url for a remote signer.
+var signerBackend string
+
+// clefSigner delegates signing to a Clef compatible signer through account_signTransaction.
+type clefSigner struct {
+	ext     *external.ExternalSigner
+	account accounts.Account
+}
+
+func newClefSigner(endpoint string) (Signer, error) {
+	ext, err := external.NewExternalSigner(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to clef at %s: %v", endpoint, err)
+	}
+	account, err := remoteAccount(ext.Accounts())
+	if err != nil {
+		return nil, err
+	}
+	return &clefSigner{ext: ext, account: account}, nil
+}
+
+func (s *clefSigner) Address() common.Address {
+	return s.account.Address
+}
+
+func (s *clefSigner) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	signed, err := s.ext.SignTx(s.account, tx, chainID)
+	if err != nil {
+		return nil, err
+	}
+	return signed, checkRemoteSignature(signed, tx, chainID, s.account.Address)
+}
+
+// remoteAccount picks the --from address out of the signer's accounts, or the first one.
+func remoteAccount(available []accounts.Account) (accounts.Account, error) {
+	if from != "" {
+		if !common.IsHexAddress(from) {
+			return accounts.Account{}, fmt.Errorf("--from must be an address with a remote signer, got %q", from)
+		}
+		address := common.HexToAddress(from)
+		for _, account := range available {
+			if account.Address == address {
+				return account, nil
+			}
+		}
+		return accounts.Account{}, fmt.Errorf("remote signer does not manage %s", address.Hex())
+	}
+	if len(available) == 0 {
+		return accounts.Account{}, fmt.Errorf("remote signer lists no accounts, pass --from <address>")
+	}
+	return available[0], nil
+}
+
+// httpSigner posts the unsigned transaction to a plain HTTP signing endpoint.
+// The request is {"from", "chainId", "tx"} with tx the hex binary encoding of
+// the unsigned transaction, and the response is {"raw"} with the signed one.
+type httpSigner struct {
+	endpoint string
+	address  common.Address
+	client   *http.Client
+}
+
+type httpSignRequest struct {
+	From    common.Address `json:"from"`
+	ChainID *hexutil.Big   `json:"chainId"`
+	Tx      hexutil.Bytes  `json:"tx"`
+}
+
+type httpSignResponse struct {
+	Raw   hexutil.Bytes `json:"raw"`
+	Error string        `json:"error,omitempty"`
+}
+
+func newHTTPSigner(endpoint string) (Signer, error) {
+	if !common.IsHexAddress(from) {
+		return nil, fmt.Errorf("--from <address> is required with an http signer")
+	}
+	return &httpSigner{
+		endpoint: endpoint,
+		address:  common.HexToAddress(from),
+		client:   &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+func (s *httpSigner) Address() common.Address {
+	return s.address
+}
+
+func (s *httpSigner) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	unsigned, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(httpSignRequest{From: s.address, ChainID: (*hexutil.Big)(chainID), Tx: unsigned})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Post(s.endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	var result httpSignResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, fmt.Errorf("invalid signer response (status %d): %s", resp.StatusCode, strings.TrimSpace(string(raw)))
+	}
+	if resp.StatusCode != http.StatusOK || result.Error != "" {
+		return nil, fmt.Errorf("signer rejected the transaction (status %d): %s", resp.StatusCode, result.Error)
+	}
+	signed := new(types.Transaction)
+	if err := signed.UnmarshalBinary(result.Raw); err != nil {
+		return nil, fmt.Errorf("invalid signed transaction from signer: %v", err)
+	}
+	return signed, checkRemoteSignature(signed, tx, chainID, s.address)
+}
+
+// checkRemoteSignature makes sure the remote signer signed what we asked for, with the expected account.
+func checkRemoteSignature(signed, unsigned *types.Transaction, chainID *big.Int, expected common.Address) error {
+	if signed.ChainId().Cmp(chainID) != 0 {
+		return fmt.Errorf("remote signer signed for chain %s, expected %s", signed.ChainId(), chainID)
+	}
+	sender, err := types.Sender(types.LatestSignerForChainID(chainID), signed)
+	if err != nil {
+		return fmt.Errorf("invalid signature from remote signer: %v", err)
+	}
+	if sender != expected {
+		return fmt.Errorf("remote signer signed with %s, expected %s", sender.Hex(), expected.Hex())
+	}
+	if field := changedTxField(signed, unsigned); field != "" {
+		return fmt.Errorf("remote signer returned a different transaction: %s changed", field)
+	}
+	return nil
+}
+
+// changedTxField names the first field that differs between the two transactions, or "" when they match.
+func changedTxField(a, b *types.Transaction) string {
+	switch {
+	case a.Type() != b.Type():
+		return "type"
+	case a.Nonce() != b.Nonce():
+		return "nonce"
+	case (a.To() == nil) != (b.To() == nil) || a.To() != nil && *a.To() != *b.To():
+		return "to"
+	case a.Value().Cmp(b.Value()) != 0:
+		return "value"
+	case a.Gas() != b.Gas():
+		return "gas"
+	case a.Type() != types.DynamicFeeTxType && a.GasPrice().Cmp(b.GasPrice()) != 0:
+		return "gasPrice"
+	case a.GasFeeCap().Cmp(b.GasFeeCap()) != 0:
+		return "maxFeePerGas"
+	case a.GasTipCap().Cmp(b.GasTipCap()) != 0:
+		return "maxPriorityFeePerGas"
+	case !bytes.Equal(a.Data(), b.Data()):
+		return "data"
+	}
+	return ""
+}
+
+func loadRemoteSigner(spec string) (Signer, error) {
+	if strings.HasPrefix(spec, "http://") || strings.HasPrefix(spec, "https://") {
+		return newHTTPSigner(spec)
+	}
+	scheme, endpoint, ok := strings.Cut(spec, ":")
+	if !ok || endpoint == "" {
+		return nil, fmt.Errorf("invalid --signer %q, expected local, clef:<url> or an http(s) url", spec)
+	}
+	if scheme != "clef" {
+		return nil, fmt.Errorf("unknown signer backend %q, expected local, clef:<url> or an http(s) url", scheme)
+	}
+	return newClefSigner(endpoint)
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var testChainID = big.NewInt(232)
+
+func testUnsignedTx() *types.Transaction {
+	to := common.HexToAddress(depositPrecompileAddress)
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID:   testChainID,
+		Nonce:     7,
+		GasTipCap: big.NewInt(1e9),
+		GasFeeCap: big.NewInt(2e9),
+		Gas:       200000,
+		To:        &to,
+		Value:     big.NewInt(0),
+		Data:      []byte{0x01, 0x02, 0x03, 0x04},
+	})
+}
+
+// standInSigner serves the plain HTTP signing protocol, passing the decoded
+// transaction through tamper before signing it with key.
+func standInSigner(t *testing.T, key *keySigner, tamper func(*types.DynamicFeeTx)) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req httpSignRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("stand-in signer: bad request: %v", err)
+			return
+		}
+		tx := new(types.Transaction)
+		if err := tx.UnmarshalBinary(req.Tx); err != nil {
+			t.Errorf("stand-in signer: bad tx: %v", err)
+			return
+		}
+		inner := &types.DynamicFeeTx{
+			ChainID:   tx.ChainId(),
+			Nonce:     tx.Nonce(),
+			GasTipCap: tx.GasTipCap(),
+			GasFeeCap: tx.GasFeeCap(),
+			Gas:       tx.Gas(),
+			To:        tx.To(),
+			Value:     tx.Value(),
+			Data:      tx.Data(),
+		}
+		chainID := req.ChainID.ToInt()
+		if tamper != nil {
+			tamper(inner)
+			chainID = inner.ChainID
+		}
+		signed, err := types.SignNewTx(key.sk, types.LatestSignerForChainID(chainID), inner)
+		if err != nil {
+			t.Errorf("stand-in signer: %v", err)
+			return
+		}
+		raw, _ := signed.MarshalBinary()
+		json.NewEncoder(w).Encode(httpSignResponse{Raw: raw})
+	}))
+}
+
+func newTestKey(t *testing.T) *keySigner {
+	sk, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return newKeySigner(sk)
+}
+
+func TestHTTPSignerSignsWithExpectedAccount(t *testing.T) {
+	key := newTestKey(t)
+	server := standInSigner(t, key, nil)
+	defer server.Close()
+
+	signer := &httpSigner{endpoint: server.URL, address: key.addr, client: server.Client()}
+	unsigned := testUnsignedTx()
+	signed, err := signer.SignTx(unsigned, testChainID)
+	if err != nil {
+		t.Fatalf("SignTx: %v", err)
+	}
+	sender, err := types.Sender(types.LatestSignerForChainID(testChainID), signed)
+	if err != nil || sender != key.addr {
+		t.Fatalf("sender = %s, %v, want %s", sender.Hex(), err, key.addr.Hex())
+	}
+}
+
+func TestHTTPSignerRejectsTamperedTransactions(t *testing.T) {
+	key := newTestKey(t)
+	other := newTestKey(t)
+	tests := []struct {
+		name   string
+		signer *keySigner
+		tamper func(*types.DynamicFeeTx)
+		want   string
+	}{
+		{"wrong sender", other, nil, "signed with"},
+		{"value", key, func(tx *types.DynamicFeeTx) { tx.Value = big.NewInt(1) }, "value changed"},
+		{"gas", key, func(tx *types.DynamicFeeTx) { tx.Gas++ }, "gas changed"},
+		{"fee cap", key, func(tx *types.DynamicFeeTx) { tx.GasFeeCap = big.NewInt(9e9) }, "maxFeePerGas changed"},
+		{"tip cap", key, func(tx *types.DynamicFeeTx) { tx.GasTipCap = big.NewInt(2e9) }, "maxPriorityFeePerGas changed"},
+		{"nonce", key, func(tx *types.DynamicFeeTx) { tx.Nonce++ }, "nonce changed"},
+		{"data", key, func(tx *types.DynamicFeeTx) { tx.Data = []byte{0xff} }, "data changed"},
+		{"to", key, func(tx *types.DynamicFeeTx) { to := common.HexToAddress("0x1"); tx.To = &to }, "to changed"},
+		{"chain id", key, func(tx *types.DynamicFeeTx) { tx.ChainID = big.NewInt(1) }, "chain 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := standInSigner(t, tt.signer, tt.tamper)
+			defer server.Close()
+
+			signer := &httpSigner{endpoint: server.URL, address: key.addr, client: server.Client()}
+			_, err := signer.SignTx(testUnsignedTx(), testChainID)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("SignTx error = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTTPSignerReportsSignerErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(httpSignResponse{Error: "denied by policy"})
+	}))
+	defer server.Close()
+
+	signer := &httpSigner{endpoint: server.URL, address: common.HexToAddress("0x1"), client: server.Client()}
+	_, err := signer.SignTx(testUnsignedTx(), testChainID)
+	if err == nil || !strings.Contains(err.Error(), "denied by policy") {
+		t.Fatalf("SignTx error = %v, want the signer's rejection", err)
+	}
+}
+
+func TestCheckRemoteSignatureLegacy(t *testing.T) {
+	key := newTestKey(t)
+	to := common.HexToAddress(depositPrecompileAddress)
+	unsigned := types.NewTx(&types.LegacyTx{Nonce: 1, GasPrice: big.NewInt(1e9), Gas: 21000, To: &to, Value: big.NewInt(0)})
+	signed, err := key.SignTx(unsigned, testChainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := checkRemoteSignature(signed, unsigned, testChainID, key.addr); err != nil {
+		t.Fatalf("checkRemoteSignature: %v", err)
+	}
+
+	repriced := types.NewTx(&types.LegacyTx{Nonce: 1, GasPrice: big.NewInt(5e9), Gas: 21000, To: &to, Value: big.NewInt(0)})
+	resigned, err := key.SignTx(repriced, testChainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := checkRemoteSignature(resigned, unsigned, testChainID, key.addr); err == nil || !strings.Contains(err.Error(), "gasPrice changed") {
+		t.Fatalf("checkRemoteSignature error = %v, want a gasPrice mismatch", err)
+	}
+}
+
+// clefStandIn implements the account_ namespace calls used by the clef backend.
+type clefStandIn struct {
+	accounts []common.Address
+}
+
+func (c *clefStandIn) Version() (string, error) {
+	return "6.0.0", nil
+}
+
+func (c *clefStandIn) List() ([]common.Address, error) {
+	return c.accounts, nil
+}
+
+func TestClefSignerChecksFromAgainstAccounts(t *testing.T) {
+	listed := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	server := rpc.NewServer()
+	if err := server.RegisterName("account", &clefStandIn{accounts: []common.Address{listed}}); err != nil {
+		t.Fatal(err)
+	}
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	setGlobal(t, &from, listed.Hex())
+	signer, err := newClefSigner(httpServer.URL)
+	if err != nil {
+		t.Fatalf("newClefSigner: %v", err)
+	}
+	if signer.Address() != listed {
+		t.Fatalf("address = %s, want %s", signer.Address().Hex(), listed.Hex())
+	}
+
+	from = "0x00000000000000000000000000000000000000bb"
+	if _, err := newClefSigner(httpServer.URL); err == nil || !strings.Contains(err.Error(), "does not manage") {
+		t.Fatalf("newClefSigner error = %v, want an unmanaged account error", err)
+	}
+}
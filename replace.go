@@ -0,0 +1,315 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/spf13/cobra"
+)
+
+// cancelGasLimit is the gas of the zero value self-transfer used to cancel a tx.
+const cancelGasLimit = 21000
+
+var (
+	bumpPercent uint64
+	bumpAfter   time.Duration
+	maxBumps    int
+	bumpFeeCap  string
+)
+
+var txCmd = &cobra.Command{
+	Use:   "tx",
+	Short: "Inspect and manage sent transactions",
+}
+
+var txSpeedupCmd = &cobra.Command{
+	Use:   "speedup <hash>",
+	Short: "Resend a pending transaction with the same nonce and higher fees",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rpcUrl, _ := cmd.Flags().GetString("rpcUrl")
+		err := replaceTx_(rpcUrl, args[0], false)
+		if err != nil {
+			return failf("Failed to speed up transaction: %v", err)
+		}
+		return nil
+	},
+}
+
+var txCancelCmd = &cobra.Command{
+	Use:   "cancel <hash>",
+	Short: "Replace a pending transaction with a zero value self-transfer",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rpcUrl, _ := cmd.Flags().GetString("rpcUrl")
+		err := replaceTx_(rpcUrl, args[0], true)
+		if err != nil {
+			return failf("Failed to cancel transaction: %v", err)
+		}
+		return nil
+	},
+}
+
+func registerTxCommands() {
+	rootCmd.PersistentFlags().Uint64Var(&bumpPercent, "bump-percent", 10, "Fee increase in percent when replacing a pending tx")
+	rootCmd.PersistentFlags().DurationVar(&bumpAfter, "bump-after", 0, "Resend with bumped fees when a tx is not mined within this duration, 0 disables")
+	rootCmd.PersistentFlags().IntVar(&maxBumps, "max-bumps", 3, "Maximum number of automatic fee bumps")
+	rootCmd.PersistentFlags().StringVar(&bumpFeeCap, "bump-fee-cap", "", "Highest gas price / max fee in wei automatic bumps may reach")
+
+	rootCmd.AddCommand(txCmd)
+	txCmd.AddCommand(txSpeedupCmd)
+	txCmd.AddCommand(txCancelCmd)
+
+	txSpeedupCmd.Flags().String("rpcUrl", "http://localhost:8545", "Exocore RPC URL")
+	txCancelCmd.Flags().String("rpcUrl", "http://localhost:8545", "Exocore RPC URL")
+}
+
+func replaceTx_(rpcUrl, txID string, cancel bool) error {
+	_, ethClient, err := connectToEthereum(rpcUrl)
+	if err != nil {
+		return err
+	}
+
+	signer, err := loadSigner()
+	if err != nil {
+		return withCode(errCodeSigner, err)
+	}
+	from := signer.Address()
+	report.From = &from
+
+	ctx := context.Background()
+	chainID, err := ethClient.ChainID(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, isPending, err := ethClient.TransactionByHash(ctx, common.HexToHash(txID))
+	if err != nil {
+		return fmt.Errorf("failed to get transaction: %v", err)
+	}
+	if !isPending {
+		return fmt.Errorf("transaction %s is already mined", txID)
+	}
+	sender, err := types.Sender(types.LatestSignerForChainID(chainID), tx)
+	if err != nil {
+		return err
+	}
+	if sender != signer.Address() {
+		return fmt.Errorf("transaction %s was sent by %s, not by the signer %s", txID, sender.Hex(), signer.Address().Hex())
+	}
+
+	fees, err := replacementFees(ctx, ethClient, tx)
+	if err != nil {
+		return err
+	}
+	replacement := replacementTx(chainID, tx, fees, cancel)
+	fmt.Fprintln(textOut, "Fees:", fees)
+
+	newID, err := signAndSend(ctx, ethClient, chainID, signer, replacement)
+	if err != nil {
+		return err
+	}
+	if cancel {
+		fmt.Fprintln(textOut, "Cancel Transaction ID:", newID)
+	} else {
+		fmt.Fprintln(textOut, "Speedup Transaction ID:", newID)
+	}
+	report.Status = "sent"
+	setResult(map[string]string{"replaced": txID})
+	if noWait {
+		return nil
+	}
+	receipt, err := waitForTransaction(ethClient, newID)
+	if receipt != nil {
+		summary := summarizeReceipt(receipt)
+		report.Receipt = &summary
+		printReceipt(summary)
+	}
+	if err != nil {
+		return err
+	}
+	report.Status = "mined"
+	return nil
+}
+
+// replacementTx rebuilds tx with the same nonce and the given fees. A cancel
+// replaces it with a zero value transfer to the sender itself.
+func replacementTx(chainID *big.Int, tx *types.Transaction, fees txFees, cancel bool) *types.Transaction {
+	to := tx.To()
+	value := tx.Value()
+	gas := tx.Gas()
+	data := tx.Data()
+	if cancel {
+		sender, _ := types.Sender(types.LatestSignerForChainID(chainID), tx)
+		to = &sender
+		value = big.NewInt(0)
+		gas = cancelGasLimit
+		data = nil
+	}
+	if fees.Type == txTypeLegacy {
+		return types.NewTx(&types.LegacyTx{
+			Nonce:    tx.Nonce(),
+			To:       to,
+			Value:    value,
+			Gas:      gas,
+			GasPrice: fees.GasPrice,
+			Data:     data,
+		})
+	}
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     tx.Nonce(),
+		To:        to,
+		Value:     value,
+		Gas:       gas,
+		GasTipCap: fees.MaxPriorityFee,
+		GasFeeCap: fees.MaxFee,
+		Data:      data,
+	})
+}
+
+// bump raises value by --bump-percent, rounding up.
+func bump(value *big.Int) *big.Int {
+	bumped := new(big.Int).Mul(value, new(big.Int).SetUint64(100+bumpPercent))
+	bumped.Add(bumped, big.NewInt(99))
+	return bumped.Div(bumped, big.NewInt(100))
+}
+
+func maxBig(a, b *big.Int) *big.Int {
+	if a.Cmp(b) >= 0 {
+		return a
+	}
+	return b
+}
+
+// replacementFees returns fees for a tx replacing tx: the original fees bumped by
+// --bump-percent, at least the current network suggestion, and the fee flags
+// when they are higher than that.
+func replacementFees(ctx context.Context, client *ethclient.Client, tx *types.Transaction) (txFees, error) {
+	if bumpPercent < 10 {
+		return txFees{}, fmt.Errorf("--bump-percent must be at least 10, nodes reject smaller replacements")
+	}
+	current, err := currentFees(ctx, client, tx.Type())
+	if err != nil {
+		return txFees{}, err
+	}
+
+	if tx.Type() == types.LegacyTxType {
+		price := maxBig(bump(tx.GasPrice()), current.GasPrice)
+		return txFees{Type: txTypeLegacy, GasPrice: price}, nil
+	}
+	if current.Type != txTypeDynamic {
+		return txFees{}, fmt.Errorf("the node reports no base fee, a 1559 transaction cannot be replaced on it")
+	}
+	tip := maxBig(bump(tx.GasTipCap()), current.MaxPriorityFee)
+	feeCap := maxBig(bump(tx.GasFeeCap()), current.MaxFee)
+	if feeCap.Cmp(tip) < 0 {
+		feeCap = tip
+	}
+	return txFees{Type: txTypeDynamic, MaxFee: feeCap, MaxPriorityFee: tip}, nil
+}
+
+// currentFees resolves the fee flags and network suggestion for a tx of the given type.
+func currentFees(ctx context.Context, client *ethclient.Client, kind uint8) (txFees, error) {
+	if kind == types.LegacyTxType {
+		if maxFee != "" || maxPriorityFee != "" {
+			return txFees{}, fmt.Errorf("the transaction is legacy, use --gas-price")
+		}
+		return legacyFees(ctx, client)
+	}
+	if gasPrice != "" {
+		return txFees{}, fmt.Errorf("the transaction is 1559, use --max-fee and --max-priority-fee")
+	}
+	return dynamicFees(ctx, client)
+}
+
+// waitWithFeeBumps waits for txID like waitForTransaction, but with --bump-after
+// it resends the tx with bumped fees each time it stays pending that long. The
+// receipt of whichever version gets mined is returned.
+func waitWithFeeBumps(client *ethclient.Client, chainID *big.Int, signer Signer, txID string) (*types.Receipt, error) {
+	if bumpAfter <= 0 {
+		return waitForTransaction(client, txID)
+	}
+	var feeCap *big.Int
+	if bumpFeeCap != "" {
+		parsed, err := parseWei("bump fee cap", bumpFeeCap)
+		if err != nil {
+			return nil, err
+		}
+		feeCap = parsed
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), waitTimeout)
+	defer cancel()
+
+	tx, _, err := client.TransactionByHash(ctx, common.HexToHash(txID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction: %v", err)
+	}
+	sent := []common.Hash{tx.Hash()}
+	lastSent := time.Now()
+	bumps := 0
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		for _, hash := range sent {
+			receipt, err := client.TransactionReceipt(ctx, hash)
+			if err == nil {
+				if hash != sent[0] {
+					fmt.Fprintln(textOut, "Mined as replacement", hash.Hex())
+				}
+				receipt, err = waitForConfirmations(ctx, client, receipt)
+				if err != nil {
+					return nil, err
+				}
+				if receipt.Status != types.ReceiptStatusSuccessful {
+					return receipt, withCode(errCodeTxFailed, fmt.Errorf("transaction failed with status: %v", receipt.Status))
+				}
+				return receipt, nil
+			}
+			if !errors.Is(err, ethereum.NotFound) {
+				return nil, err
+			}
+		}
+
+		// A failed bump counts against --max-bumps but does not stop the wait:
+		// the versions already sent can still be mined.
+		if bumps < maxBumps && time.Since(lastSent) >= bumpAfter {
+			fees, err := replacementFees(ctx, client, tx)
+			switch {
+			case err != nil:
+				fmt.Fprintln(textOut, "Fee bump failed:", err)
+				bumps++
+			case feeCap != nil && ((fees.GasPrice != nil && fees.GasPrice.Cmp(feeCap) > 0) || (fees.MaxFee != nil && fees.MaxFee.Cmp(feeCap) > 0)):
+				fmt.Fprintln(textOut, "Bumped fees would exceed --bump-fee-cap, waiting without further bumps")
+				bumps = maxBumps
+			default:
+				replacement := replacementTx(chainID, tx, fees, false)
+				fmt.Fprintf(textOut, "Transaction %s not mined after %s, bumping fees: %s\n", sent[len(sent)-1].Hex(), bumpAfter, fees)
+				newID, err := signAndSend(ctx, client, chainID, signer, replacement)
+				if err != nil {
+					fmt.Fprintln(textOut, "Fee bump failed:", err)
+				} else {
+					tx = replacement
+					sent = append(sent, common.HexToHash(newID))
+				}
+				bumps++
+			}
+			lastSent = time.Now()
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("failed to wait for transaction to be mined: %w", ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestReplacementFees(t *testing.T) {
+	setGlobal(t, &bumpPercent, 10)
+	setGlobal(t, &maxFee, "")
+	setGlobal(t, &maxPriorityFee, "")
+	setGlobal(t, &gasPrice, "")
+
+	dynamic := testUnsignedTx()
+	fees, err := replacementFees(context.Background(), feeClient(t, big.NewInt(1)), dynamic)
+	if err != nil {
+		t.Fatalf("replacementFees: %v", err)
+	}
+	if fees.MaxPriorityFee.Cmp(big.NewInt(1.1e9)) != 0 || fees.MaxFee.Cmp(big.NewInt(2.2e9)) != 0 {
+		t.Errorf("fees = %s, want the original fees bumped by 10%%", fees)
+	}
+
+	if _, err := replacementFees(context.Background(), feeClient(t, nil), dynamic); err == nil || !strings.Contains(err.Error(), "no base fee") {
+		t.Errorf("replacementFees error = %v, want a no base fee error", err)
+	}
+
+	legacy := types.NewTx(&types.LegacyTx{Nonce: 1, GasPrice: big.NewInt(1e9), Gas: 21000})
+	fees, err = replacementFees(context.Background(), feeClient(t, nil), legacy)
+	if err != nil {
+		t.Fatalf("replacementFees: %v", err)
+	}
+	if fees.Type != txTypeLegacy || fees.GasPrice.Cmp(big.NewInt(3e9)) != 0 {
+		t.Errorf("fees = %s, want the suggested gas price above the bump", fees)
+	}
+}
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"golang.org/x/term"
+)
+
+const (
+	// privateKeyEnv lets scripts provide a hex private key without putting it on the command line
+	privateKeyEnv = "ASSETCLI_PRIVATE_KEY"
+	// keyNamesFile maps user-chosen key names to keystore addresses
+	keyNamesFile = "names.json"
+)
+
+var (
+	from           string
+	keystoreDir    string
+	passphraseFile string
+
+	signersMu sync.Mutex
+	signers   = make(map[string]Signer)
+)
+
+// Signer signs transactions on behalf of a single account.
+type Signer interface {
+	Address() common.Address
+	SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+}
+
+// keySigner signs with an in-memory private key.
+type keySigner struct {
+	sk   *ecdsa.PrivateKey
+	addr common.Address
+}
+
+func newKeySigner(sk *ecdsa.PrivateKey) *keySigner {
+	return &keySigner{sk: sk, addr: crypto.PubkeyToAddress(sk.PublicKey)}
+}
+
+func (s *keySigner) Address() common.Address {
+	return s.addr
+}
+
+func (s *keySigner) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return types.SignTx(tx, types.LatestSignerForChainID(chainID), s.sk)
+}
+
+// keystoreSigner signs with an encrypted V3 keystore account.
+type keystoreSigner struct {
+	ks         *keystore.KeyStore
+	account    accounts.Account
+	passphrase string
+}
+
+func (s *keystoreSigner) Address() common.Address {
+	return s.account.Address
+}
+
+func (s *keystoreSigner) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return s.ks.SignTxWithPassphrase(s.account, s.passphrase, tx, chainID)
+}
+
+// loadSigner resolves the signer for the current command. A remote --signer
+// wins, then the keystore account selected by --from, then the --mnemonic-file
+// HD account, then --privateKey, then the ASSETCLI_PRIVATE_KEY env var.
+// Signers are kept for the process, so the steps of a plan unlock a key once.
+func loadSigner() (Signer, error) {
+	key := strings.Join([]string{signerBackend, from, keystoreDir, passphraseFile, mnemonicFile, hdPath, fmt.Sprint(accountIndex), privateKey}, "\x00")
+	signersMu.Lock()
+	defer signersMu.Unlock()
+	if signer, ok := signers[key]; ok {
+		return signer, nil
+	}
+	signer, err := newSigner()
+	if err != nil {
+		return nil, err
+	}
+	signers[key] = signer
+	return signer, nil
+}
+
+func newSigner() (Signer, error) {
+	if signerBackend != "" && signerBackend != "local" {
+		return loadRemoteSigner(signerBackend)
+	}
+	if from != "" {
+		return loadKeystoreSigner(from)
+	}
+	if mnemonicFile != "" {
+		return mnemonicSigner(hdPath, accountIndex)
+	}
+	if privateKey != "" {
+		fmt.Fprintln(os.Stderr, "Warning: --privateKey exposes the key in shell history, prefer --from or", privateKeyEnv)
+		return signerFromHex(privateKey)
+	}
+	if key := os.Getenv(privateKeyEnv); key != "" {
+		return signerFromHex(key)
+	}
+	return nil, fmt.Errorf("no signing key: use --from <name|address> or --mnemonic-file, set %s or pass --privateKey", privateKeyEnv)
+}
+
+func signerFromHex(key string) (Signer, error) {
+	sk, err := crypto.HexToECDSA(strings.TrimPrefix(strings.TrimSpace(key), "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %v", err)
+	}
+	return newKeySigner(sk), nil
+}
+
+func loadKeystoreSigner(nameOrAddress string) (Signer, error) {
+	ks := openKeystore()
+	account, err := findKeystoreAccount(ks, nameOrAddress)
+	if err != nil {
+		return nil, err
+	}
+	passphrase, err := readPassphrase(fmt.Sprintf("Passphrase for %s: ", account.Address.Hex()), false)
+	if err != nil {
+		return nil, err
+	}
+	// fail early on a wrong passphrase instead of at signing time
+	if err := ks.Unlock(account, passphrase); err != nil {
+		return nil, fmt.Errorf("failed to unlock %s: %v", account.Address.Hex(), err)
+	}
+	if err := ks.Lock(account.Address); err != nil {
+		return nil, err
+	}
+	return &keystoreSigner{ks: ks, account: account, passphrase: passphrase}, nil
+}
+
+func defaultKeystoreDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".assetcli", "keystore")
+	}
+	return filepath.Join(home, ".assetcli", "keystore")
+}
+
+func openKeystore() *keystore.KeyStore {
+	return keystore.NewKeyStore(keystoreDir, keystore.StandardScryptN, keystore.StandardScryptP)
+}
+
+func findKeystoreAccount(ks *keystore.KeyStore, nameOrAddress string) (accounts.Account, error) {
+	address := nameOrAddress
+	if !common.IsHexAddress(nameOrAddress) {
+		names, err := loadKeyNames()
+		if err != nil {
+			return accounts.Account{}, err
+		}
+		addr, ok := names[nameOrAddress]
+		if !ok {
+			return accounts.Account{}, fmt.Errorf("no key named %q in %s", nameOrAddress, keystoreDir)
+		}
+		address = addr
+	}
+	account, err := ks.Find(accounts.Account{Address: common.HexToAddress(address)})
+	if err != nil {
+		return accounts.Account{}, fmt.Errorf("key %s not found in %s: %v", nameOrAddress, keystoreDir, err)
+	}
+	return account, nil
+}
+
+func loadKeyNames() (map[string]string, error) {
+	names := make(map[string]string)
+	raw, err := os.ReadFile(filepath.Join(keystoreDir, keyNamesFile))
+	if os.IsNotExist(err) {
+		return names, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, &names); err != nil {
+		return nil, fmt.Errorf("invalid %s: %v", keyNamesFile, err)
+	}
+	return names, nil
+}
+
+func saveKeyName(name string, address common.Address) error {
+	names, err := loadKeyNames()
+	if err != nil {
+		return err
+	}
+	if existing, ok := names[name]; ok && !strings.EqualFold(existing, address.Hex()) {
+		return fmt.Errorf("key name %q is already used by %s", name, existing)
+	}
+	names[name] = address.Hex()
+	raw, err := json.MarshalIndent(names, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(keystoreDir, 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(keystoreDir, keyNamesFile), raw, 0o600)
+}
+
+// readPassphrase reads the passphrase from --passphrase-file, or prompts for it on
+// the terminal without echo. confirm asks a second time when creating a new key.
+func readPassphrase(prompt string, confirm bool) (string, error) {
+	if passphraseFile != "" {
+		raw, err := os.ReadFile(passphraseFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read passphrase file: %v", err)
+		}
+		return strings.TrimRight(string(raw), "\r\n"), nil
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", fmt.Errorf("no terminal to prompt for a passphrase, use --passphrase-file")
+	}
+	passphrase, err := promptHidden(prompt)
+	if err != nil {
+		return "", err
+	}
+	if confirm {
+		again, err := promptHidden("Repeat passphrase: ")
+		if err != nil {
+			return "", err
+		}
+		if again != passphrase {
+			return "", fmt.Errorf("passphrases do not match")
+		}
+	}
+	return passphrase, nil
+}
+
+func promptHidden(prompt string) (string, error) {
+	fmt.Fprint(os.Stderr, prompt)
+	raw, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+	return string(raw), nil
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+var (
+	force  bool
+	dryRun bool
+)
+
+// simulationResult is the preflight eth_call of a precompile method, unpacked
+// against the method's ABI outputs.
+type simulationResult struct {
+	Success bool         `json:"success"`
+	Outputs []decodedArg `json:"outputs,omitempty"`
+	Error   string       `json:"error,omitempty"`
+	// Block is the block the call ran against, set for post-transaction outputs
+	Block uint64 `json:"block,omitempty"`
+}
+
+func registerSimulationFlags() {
+	rootCmd.PersistentFlags().BoolVar(&force, "force", false, "Send the transaction even if the preflight simulation fails")
+	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "Stop after the preflight simulation")
+}
+
+// simulate runs data as a call from from at the given block (nil for latest).
+// A revert or a false success output is reported in the result, not as an error.
+func simulate(ctx context.Context, client *ethclient.Client, from, to common.Address, contractAbi abi.ABI, data []byte, block *big.Int) (*simulationResult, error) {
+	if len(data) < 4 {
+		return nil, fmt.Errorf("calldata too short: %d bytes", len(data))
+	}
+	method, err := contractAbi.MethodById(data[:4])
+	if err != nil {
+		return nil, err
+	}
+	msg := ethereum.CallMsg{
+		From: from,
+		To:   &to,
+		Data: data,
+	}
+	result, err := client.CallContract(ctx, msg, block)
+	if err != nil {
+		reason, ok := revertReason(err)
+		if !ok {
+			reason = err.Error()
+		}
+		return &simulationResult{Error: reason}, nil
+	}
+	values, err := method.Outputs.Unpack(result)
+	if err != nil {
+		return &simulationResult{Error: fmt.Sprintf("cannot decode %s output %x: %v", method.Name, result, err)}, nil
+	}
+
+	sim := &simulationResult{Success: true, Outputs: namedValues(method.Outputs, values)}
+	for i, output := range method.Outputs {
+		if output.Name != "success" {
+			continue
+		}
+		if ok, isBool := values[i].(bool); isBool && !ok {
+			sim.Success = false
+			sim.Error = method.Name + " returned success=false"
+		}
+	}
+	return sim, nil
+}
+
+func printSimulation(sim *simulationResult) {
+	if sim.Success {
+		fmt.Fprintln(textOut, "Simulation succeeded")
+	} else {
+		fmt.Fprintln(textOut, "Simulation failed:", sim.Error)
+	}
+	for _, output := range sim.Outputs {
+		fmt.Fprintf(textOut, "  %s: %v\n", output.Name, output.Value)
+	}
+}
+
+// preflight simulates the call and refuses to continue on failure unless --force is set.
+func preflight(ctx context.Context, client *ethclient.Client, from, to common.Address, contractAbi abi.ABI, data []byte) (*simulationResult, error) {
+	sim, err := simulate(ctx, client, from, to, contractAbi, data, nil)
+	if err != nil {
+		return nil, err
+	}
+	printSimulation(sim)
+	report.Simulation = sim
+	if !sim.Success {
+		if !force {
+			return sim, withCode(errCodeSimulationFailed, fmt.Errorf("preflight simulation failed, not sending (use --force to send anyway): %s", sim.Error))
+		}
+		fmt.Fprintln(textOut, "Sending anyway because of --force")
+	}
+	return sim, nil
+}
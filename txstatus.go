@@ -0,0 +1,258 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/spf13/cobra"
+)
+
+const (
+	txStatusPending = "pending"
+	txStatusMined   = "mined"
+	txStatusFailed  = "failed"
+	txStatusDropped = "dropped"
+)
+
+var (
+	confirmations uint64
+	waitTimeout   time.Duration
+	noWait        bool
+)
+
+// txState is where a sent transaction stands on the node.
+type txState struct {
+	Hash          common.Hash    `json:"hash"`
+	Status        string         `json:"status"`
+	Block         uint64         `json:"block,omitempty"`
+	Confirmations uint64         `json:"confirmations,omitempty"`
+	Receipt       *types.Receipt `json:"-"`
+}
+
+func (s txState) String() string {
+	switch s.Status {
+	case txStatusMined, txStatusFailed:
+		return fmt.Sprintf("%s %s block=%d confirmations=%d", s.Hash.Hex(), s.Status, s.Block, s.Confirmations)
+	}
+	return fmt.Sprintf("%s %s", s.Hash.Hex(), s.Status)
+}
+
+var txWaitCmd = &cobra.Command{
+	Use:   "wait <hash>...",
+	Short: "Wait for sent transactions to be mined with --confirmations",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rpcUrl, _ := cmd.Flags().GetString("rpcUrl")
+		err := txWait_(rpcUrl, args)
+		if err != nil {
+			return failf("Failed to wait for transactions: %v", err)
+		}
+		return nil
+	},
+}
+
+var txStatusCmd = &cobra.Command{
+	Use:   "status <hash>...",
+	Short: "Report whether transactions are pending, mined, failed or dropped",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rpcUrl, _ := cmd.Flags().GetString("rpcUrl")
+		err := txStatus_(rpcUrl, args)
+		if err != nil {
+			return failf("Failed to get transaction status: %v", err)
+		}
+		return nil
+	},
+}
+
+func registerWaitFlags() {
+	rootCmd.PersistentFlags().Uint64Var(&confirmations, "confirmations", 1, "Number of blocks, including the tx's own, to wait for")
+	rootCmd.PersistentFlags().DurationVar(&waitTimeout, "timeout", 5*time.Minute, "How long to wait for a tx to be mined and confirmed")
+	rootCmd.PersistentFlags().BoolVar(&noWait, "no-wait", false, "Print the tx hash and exit without waiting, check it later with tx wait / tx status")
+
+	txCmd.AddCommand(txWaitCmd)
+	txCmd.AddCommand(txStatusCmd)
+
+	txWaitCmd.Flags().String("rpcUrl", "http://localhost:8545", "Exocore RPC URL")
+	txStatusCmd.Flags().String("rpcUrl", "http://localhost:8545", "Exocore RPC URL")
+}
+
+// getTxState looks up hash on the node. A tx the node no longer knows, or whose
+// nonce was used by another tx of the same sender, is dropped.
+func getTxState(ctx context.Context, client *ethclient.Client, hash common.Hash) (txState, error) {
+	state := txState{Hash: hash, Status: txStatusPending}
+
+	receipt, err := client.TransactionReceipt(ctx, hash)
+	if err == nil {
+		head, err := client.BlockNumber(ctx)
+		if err != nil {
+			return state, err
+		}
+		state.Receipt = receipt
+		state.Block = receipt.BlockNumber.Uint64()
+		if head >= state.Block {
+			state.Confirmations = head - state.Block + 1
+		}
+		state.Status = txStatusMined
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			state.Status = txStatusFailed
+		}
+		return state, nil
+	}
+	if !errors.Is(err, ethereum.NotFound) {
+		return state, err
+	}
+
+	tx, isPending, err := client.TransactionByHash(ctx, hash)
+	if errors.Is(err, ethereum.NotFound) {
+		state.Status = txStatusDropped
+		return state, nil
+	}
+	if err != nil {
+		return state, err
+	}
+	if !isPending {
+		// included, but the receipt is not indexed yet
+		return state, nil
+	}
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return state, err
+	}
+	sender, err := types.Sender(types.LatestSignerForChainID(chainID), tx)
+	if err != nil {
+		return state, err
+	}
+	nonce, err := client.NonceAt(ctx, sender, nil)
+	if err != nil {
+		return state, err
+	}
+	if nonce > tx.Nonce() {
+		state.Status = txStatusDropped
+	}
+	return state, nil
+}
+
+// waitForConfirmations waits until receipt's block is --confirmations deep. If a
+// reorg moves the tx to another block the new receipt is returned; if it drops
+// the tx, waiting continues until it is mined again or the context expires.
+func waitForConfirmations(ctx context.Context, client *ethclient.Client, receipt *types.Receipt) (*types.Receipt, error) {
+	if confirmations <= 1 {
+		return receipt, nil
+	}
+	fmt.Fprintf(textOut, "Waiting for %d confirmations\n", confirmations)
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		head, err := client.BlockNumber(ctx)
+		if err != nil {
+			return receipt, err
+		}
+		if head+1 >= receipt.BlockNumber.Uint64()+confirmations {
+			current, err := client.TransactionReceipt(ctx, receipt.TxHash)
+			if err == nil {
+				if current.BlockHash == receipt.BlockHash {
+					return current, nil
+				}
+				fmt.Fprintf(textOut, "Transaction %s moved from block %d to %d\n", receipt.TxHash.Hex(), receipt.BlockNumber, current.BlockNumber)
+				receipt = current
+			} else if !errors.Is(err, ethereum.NotFound) {
+				return receipt, err
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return receipt, fmt.Errorf("timed out waiting for %d confirmations: %w", confirmations, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+func txStatus_(rpcUrl string, hashes []string) error {
+	_, ethClient, err := connectToEthereum(rpcUrl)
+	if err != nil {
+		return err
+	}
+	var states []txState
+	for _, hash := range hashes {
+		state, err := getTxState(context.Background(), ethClient, common.HexToHash(hash))
+		if err != nil {
+			return err
+		}
+		states = append(states, state)
+		fmt.Fprintln(textOut, state)
+	}
+	setResult(states)
+	return nil
+}
+
+// txWait_ polls all hashes until each is confirmed, failed or dropped, so a CI
+// job can fan out many --no-wait operations and check them in one step.
+func txWait_(rpcUrl string, hashes []string) error {
+	_, ethClient, err := connectToEthereum(rpcUrl)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), waitTimeout)
+	defer cancel()
+
+	remaining := make(map[common.Hash]bool, len(hashes))
+	for _, hash := range hashes {
+		remaining[common.HexToHash(hash)] = true
+	}
+	failed := 0
+	var settled []txState
+	defer func() { setResult(settled) }()
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		for _, hash := range hashes {
+			h := common.HexToHash(hash)
+			if !remaining[h] {
+				continue
+			}
+			state, err := getTxState(ctx, ethClient, h)
+			if err != nil {
+				if ctx.Err() != nil {
+					break
+				}
+				return err
+			}
+			if state.Status == txStatusPending || (state.Status == txStatusMined && state.Confirmations < confirmations) {
+				continue
+			}
+			delete(remaining, h)
+			settled = append(settled, state)
+			fmt.Fprintln(textOut, state)
+			if state.Receipt != nil {
+				printReceipt(summarizeReceipt(state.Receipt))
+			}
+			if state.Status != txStatusMined {
+				failed++
+			}
+		}
+		if len(remaining) == 0 {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			for _, hash := range hashes {
+				if remaining[common.HexToHash(hash)] {
+					fmt.Fprintln(textOut, hash, "still pending")
+				}
+			}
+			return withCode(errCodeTimeout, fmt.Errorf("timed out after %s with %d transactions unconfirmed", waitTimeout, len(remaining)))
+		case <-ticker.C:
+		}
+	}
+	if failed > 0 {
+		return withCode(errCodeTxFailed, fmt.Errorf("%d of %d transactions failed or were dropped", failed, len(hashes)))
+	}
+	return nil
+}
@@ -1 +1 @@
-./assetcli undelegate --rpcUrl http://localhost:9545 --staker 0xa53f68563D22EB0dAFAA871b6C08a6852f91d627 --amount -1000000000000000000000 --privateKey C26A874A75B028638D477DDF31EB8627899CB505798DF70D2DD2A631F9CAE7A4  --defaultAssetID 0x83E6850591425e3C1E263c054f4466838B9Bd9e4 --layerZeroID 40161 --operator exo1hj3qk6wg7se6l8g3s3ept7aas37dc75fk3lm2s
+./assetcli undelegate --rpcUrl http://localhost:9545 --staker 0xa53f68563D22EB0dAFAA871b6C08a6852f91d627 --amount 1000000000000000000000 --privateKey C26A874A75B028638D477DDF31EB8627899CB505798DF70D2DD2A631F9CAE7A4  --defaultAssetID 0x83E6850591425e3C1E263c054f4466838B9Bd9e4 --layerZeroID 40161 --operator exo1hj3qk6wg7se6l8g3s3ept7aas37dc75fk3lm2s
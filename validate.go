@@ -0,0 +1,220 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// requiredGlobalAnnotation lists root flags a command cannot do without, such
+// as --defaultAssetID, which MarkFlagRequired cannot express for inherited flags.
+const requiredGlobalAnnotation = "assetcli_required_global_flags"
+
+// flagValidators check flags by name for every command, so bad input is
+// reported before any key is loaded or any node is dialed.
+var flagValidators = map[string]func(value string) error{
+	"staker":              validateHexAddress,
+	"avsAddress":          validateHexAddress,
+	"assetAddress":        validateAssetAddress,
+	"token":               validateAssetAddress,
+	"defaultAssetID":      validateAssetAddress,
+	"pubkey":              validatePubkey,
+	"operator":            validateOperator,
+	"redelegateOperator":  validateOperator,
+	"amount":              validateAmountSign,
+	"numerator":           validateAmountSign,
+	"denominator":         validateAmountSign,
+	"rpcUrl":              validateURL,
+	"apiUrl":              validateURL,
+	"deposit-precompile":  validateHexAddress,
+	"delegate-precompile": validateHexAddress,
+	"reward-precompile":   validateHexAddress,
+}
+
+// registerValidation marks the flags each command needs and installs the
+// shared flag checks, run after the profile is applied. It runs after all
+// flags are defined.
+func registerValidation() {
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if err := setupOutput(cmd); err != nil {
+			return err
+		}
+		if err := applyProfile(cmd); err != nil {
+			return withCode(errCodeInvalidArguments, err)
+		}
+		if err := applyAliases(cmd); err != nil {
+			return withCode(errCodeInvalidArguments, err)
+		}
+		return withCode(errCodeInvalidArguments, validateFlags(cmd, args))
+	}
+	rootCmd.SilenceUsage = true
+	// fatalf reports the error once Execute returns
+	rootCmd.SilenceErrors = true
+
+	required := map[*cobra.Command][]string{
+		depositCmd:                      {"staker", "amount"},
+		withdrawLSTCmd:                  {"staker", "amount"},
+		delegateCmd:                     {"staker", "operator", "amount"},
+		undelegateCmd:                   {"staker", "operator", "amount"},
+		selfDelegateCmd:                 {"staker", "operator"},
+		cancelSelfDelegateCmd:           {"staker"},
+		depositNSTCmd:                   {"staker", "amount", "pubkey"},
+		withdrawNSTCmd:                  {"staker", "amount", "pubkey"},
+		registerTokenCmd:                {"assetAddress", "name"},
+		updateTokenCmd:                  {"assetAddress"},
+		registerOrUpdateClientChainCmd:  {"clientChainID", "name"},
+		claimRewardCmd:                  {"staker"},
+		fundAVSRewardCmd:                {"rewardAssetChainID", "avsAddress", "assetAddress", "amount"},
+		isRegisteredRewardTokenCmd:      {"clientChainID", "token"},
+		registerRewardTokenCmd:          {"token", "symbol"},
+		setAVSEpochRewardCmd:            {"denomination", "amount"},
+		setOperatorRewardProportionsCmd: {"operator", "numerator", "denominator"},
+		setStakerRewardParamsCmd:        {"staker"},
+		undelegateRewardCmd:             {"rewardAssetChainID", "staker", "operator", "amount"},
+		updateRewardTokenCmd:            {"token"},
+		withdrawCommissionCmd:           {"rewardAssetChainID", "operator", "amount"},
+		withdrawIMUATokenCommissionCmd:  {"operator", "receiptAddress", "amount"},
+		withdrawIMUATokenRewardCmd:      {"staker", "receiptAddress", "amount"},
+		withdrawRewardCmd:               {"rewardAssetChainID", "staker", "amount"},
+		isRegisteredClientChainCmd:      {"clientChainID"},
+		stakerAssetsCmd:                 {"staker"},
+		operatorAssetsCmd:               {"operator"},
+		associatedOperatorCmd:           {"staker"},
+	}
+	for cmd, flags := range required {
+		for _, name := range flags {
+			if err := cmd.MarkFlagRequired(name); err != nil {
+				panic(fmt.Sprintf("%s: %v", cmd.Name(), err))
+			}
+		}
+	}
+
+	for _, cmd := range []*cobra.Command{depositCmd, withdrawLSTCmd, delegateCmd, undelegateCmd, undelegateRewardCmd, withdrawCommissionCmd, withdrawRewardCmd} {
+		requireGlobalFlags(cmd, "defaultAssetID")
+	}
+}
+
+func requireGlobalFlags(cmd *cobra.Command, names ...string) {
+	if cmd.Annotations == nil {
+		cmd.Annotations = make(map[string]string)
+	}
+	cmd.Annotations[requiredGlobalAnnotation] = strings.Join(names, ",")
+}
+
+// validateFlags checks required flags, runs the flag validators on every flag
+// the user set and checks the command's required root flags, reporting all
+// problems at once.
+func validateFlags(cmd *cobra.Command, args []string) error {
+	var problems []string
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if flagRequired(f) && !f.Changed {
+			problems = append(problems, fmt.Sprintf("--%s is required", f.Name))
+		}
+	})
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		validator, ok := flagValidators[f.Name]
+		if !ok {
+			return
+		}
+		if err := validator(f.Value.String()); err != nil {
+			problems = append(problems, fmt.Sprintf("--%s: %v", f.Name, err))
+		}
+	})
+	if names := cmd.Annotations[requiredGlobalAnnotation]; names != "" {
+		for _, name := range strings.Split(names, ",") {
+			if f := cmd.Flags().Lookup(name); f != nil && f.Value.String() == "" {
+				problems = append(problems, fmt.Sprintf("--%s is required by %s", name, cmd.Name()))
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid arguments:\n  %s", strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+// flagRequired reports whether f was marked with MarkFlagRequired.
+func flagRequired(f *pflag.Flag) bool {
+	required, ok := f.Annotations[cobra.BashCompOneRequiredFlag]
+	return ok && len(required) > 0 && required[0] == "true"
+}
+
+// validateHexBytes checks that value is 0x prefixed hex of one of the given byte lengths.
+func validateHexBytes(value string, lengths ...int) error {
+	raw, err := hexutil.Decode(value)
+	if err != nil {
+		return fmt.Errorf("%q is not 0x prefixed hex: %v", value, err)
+	}
+	for _, length := range lengths {
+		if len(raw) == length {
+			return nil
+		}
+	}
+	return fmt.Errorf("%q is %d bytes, expected %s", value, len(raw), joinLengths(lengths))
+}
+
+func joinLengths(lengths []int) string {
+	parts := make([]string, len(lengths))
+	for i, length := range lengths {
+		parts[i] = fmt.Sprint(length)
+	}
+	return strings.Join(parts, " or ")
+}
+
+func validateHexAddress(value string) error {
+	return validateHexBytes(value, 20)
+}
+
+// validateAssetAddress checks a 20 or 32 byte asset address. The "nst"
+// shortcut is only resolved by --asset, so it is rejected here with a pointer there.
+func validateAssetAddress(value string) error {
+	if strings.EqualFold(value, "nst") {
+		return fmt.Errorf("%q is not an asset address, use --asset nst for the native restaking asset", value)
+	}
+	return validateHexBytes(value, 20, 32)
+}
+
+// validatePubkey checks the 32-byte validator pubkey of NST deposits and withdrawals.
+func validatePubkey(value string) error {
+	if !strings.HasPrefix(value, "0x") {
+		value = "0x" + value
+	}
+	return validateHexBytes(value, 32)
+}
+
+func validateOperator(value string) error {
+	if value == "" {
+		return nil
+	}
+	_, err := checkOperator(value)
+	return err
+}
+
+// validateAmountSign rejects negative amounts up front; the full parse, which
+// needs the asset's decimals, happens in parseAmount.
+func validateAmountSign(value string) error {
+	if strings.HasPrefix(strings.TrimSpace(value), "-") {
+		return fmt.Errorf("%s is negative, amounts must be positive", value)
+	}
+	return nil
+}
+
+func validateURL(value string) error {
+	u, err := url.Parse(value)
+	if err != nil {
+		return err
+	}
+	switch u.Scheme {
+	case "http", "https", "ws", "wss":
+	default:
+		return fmt.Errorf("%q is not an http(s) or ws(s) URL", value)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("%q has no host", value)
+	}
+	return nil
+}
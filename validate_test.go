@@ -0,0 +1,45 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAssetFlagsRejectNSTShortcut(t *testing.T) {
+	for _, name := range assetFlags {
+		t.Run(name, func(t *testing.T) {
+			validate := flagValidators[name]
+			if validate == nil {
+				t.Fatalf("--%s has no validator", name)
+			}
+			if err := validate("nst"); err == nil || !strings.Contains(err.Error(), "--asset nst") {
+				t.Errorf("--%s nst: error = %v, want a pointer to --asset nst", name, err)
+			}
+			for _, value := range []string{benchTestAsset, "0x" + strings.Repeat("ab", 32)} {
+				if err := validate(value); err != nil {
+					t.Errorf("--%s %s: %v", name, value, err)
+				}
+				if _, err := assetToBytes(value); err != nil {
+					t.Errorf("assetToBytes(%s): %v", value, err)
+				}
+			}
+		})
+	}
+}
+
+func TestAssetAliasResolvesNST(t *testing.T) {
+	reg := &registry{}
+	asset, err := reg.resolveAsset("NST", 40217)
+	if err != nil || asset == nil {
+		t.Fatalf("resolveAsset: %v, %v", asset, err)
+	}
+	// the address --asset nst fills in must pass the flag checks it is then subject to
+	for _, name := range assetFlags {
+		if err := flagValidators[name](asset.Address); err != nil {
+			t.Errorf("--%s %s: %v", name, asset.Address, err)
+		}
+	}
+	if _, err := assetToBytes(asset.Address); err != nil {
+		t.Errorf("assetToBytes(%s): %v", asset.Address, err)
+	}
+}